@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/openshift/crd-schema-checker/pkg/cmd/options"
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
 	admissionv1 "k8s.io/api/admission/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,6 +14,10 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+// FindingsAuditAnnotation is the audit annotation that holds the json encoded findings of every comparator, so
+// consumers of the response can filter and group them without parsing the warnings.
+const FindingsAuditAnnotation = "findings"
+
 type AdmissionHook struct {
 	ComparatorConfig *options.ComparatorConfig
 }
@@ -64,7 +69,7 @@ func (a *AdmissionHook) Validate(admissionSpec *admissionv1.AdmissionRequest) *a
 		}
 	}
 
-	comparisonResults, errs := a.ComparatorConfig.ComparatorRegistry.Compare(existingCRD, newCRD, a.ComparatorConfig.ComparatorNames...)
+	comparisonResults, errs := a.ComparatorConfig.Compare(existingCRD, newCRD)
 	if len(errs) > 0 {
 		status.Allowed = false
 		status.Result = &metav1.Status{
@@ -84,12 +89,16 @@ func (a *AdmissionHook) Validate(admissionSpec *admissionv1.AdmissionRequest) *a
 
 	errorCauses := []metav1.StatusCause{}
 	for _, comparisonResult := range comparisonResults {
-		for _, msg := range comparisonResult.Errors {
+		for _, finding := range comparisonResult.Findings {
+			if finding.Severity != manifestcomparators.SeverityError {
+				continue
+			}
 			status.Allowed = false
 			errorCauses = append(errorCauses,
 				metav1.StatusCause{
 					Type:    metav1.CauseType(comparisonResult.Name),
-					Message: msg,
+					Message: finding.Message,
+					Field:   finding.Field,
 				})
 		}
 	}
@@ -103,16 +112,33 @@ func (a *AdmissionHook) Validate(admissionSpec *admissionv1.AdmissionRequest) *a
 		}
 	}
 
+	findings := []manifestcomparators.Finding{}
 	for _, comparisonResult := range comparisonResults {
-		for _, msg := range comparisonResult.Warnings {
-			status.Warnings = append(status.Warnings, fmt.Sprintf("%q: %v", comparisonResult.Name, msg))
+		findings = append(findings, comparisonResult.Findings...)
+	}
+	for _, finding := range findings {
+		if finding.Severity == manifestcomparators.SeverityWarning {
+			status.Warnings = append(status.Warnings, fmt.Sprintf("%q: %v", finding.Comparator, finding.Message))
 		}
 	}
-	for _, comparisonResult := range comparisonResults {
-		for _, msg := range comparisonResult.Infos {
+	for _, finding := range findings {
+		if finding.Severity == manifestcomparators.SeverityInfo {
 			// better than nothing for info?
-			status.Warnings = append(status.Warnings, fmt.Sprintf("fyi: %q: %v", comparisonResult.Name, msg))
+			status.Warnings = append(status.Warnings, fmt.Sprintf("fyi: %q: %v", finding.Comparator, finding.Message))
+		}
+	}
+
+	if len(findings) > 0 {
+		findingsJSON, err := json.Marshal(findings)
+		if err != nil {
+			status.Allowed = false
+			status.Result = &metav1.Status{
+				Status: metav1.StatusFailure, Code: http.StatusInternalServerError, Reason: metav1.StatusReasonInternalError,
+				Message: fmt.Sprintf("failed to marshal findings: %v", err.Error()),
+			}
+			return status
 		}
+		status.AuditAnnotations = map[string]string{FindingsAuditAnnotation: string(findingsJSON)}
 	}
 
 	return status
@@ -0,0 +1,282 @@
+package approvals
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
+	"gopkg.in/yaml.v2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const digestPrefix = "sha256:"
+
+// ApprovalList is the content of an approvals file.
+type ApprovalList struct {
+	Approvals []Approval `yaml:"approvals"`
+}
+
+// Approval allows a single error for a single change.  The digest covers the identity of the error and the old and new
+// schema of the field, so editing the change after it was approved invalidates the approval.
+type Approval struct {
+	CRD        string `yaml:"crd"`
+	Comparator string `yaml:"comparator"`
+	Version    string `yaml:"version"`
+	Field      string `yaml:"field"`
+
+	// Digest is the ChangeDigest of the approved change.  It is the message that is signed.
+	Digest string `yaml:"digest"`
+	// Approver is informational, the approval is attributed to the trusted key that verified the signature.
+	Approver string `yaml:"approver,omitempty"`
+	// Signature is either a base64 encoded ed25519 signature of Digest or an armored signature of Digest created by
+	// `ssh-keygen -Y sign -n crd-schema-checker`.
+	Signature string `yaml:"signature,omitempty"`
+}
+
+func ReadApprovalsFile(filename string) (*ApprovalList, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read approvals file: %w", err)
+	}
+	return ReadApprovals(content)
+}
+
+func ReadApprovals(content []byte) (*ApprovalList, error) {
+	ret := &ApprovalList{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, fmt.Errorf("cannot decode approvals: %w", err)
+	}
+
+	errs := []error{}
+	for i, approval := range ret.Approvals {
+		if len(approval.CRD) == 0 || len(approval.Comparator) == 0 || len(approval.Digest) == 0 || len(approval.Signature) == 0 {
+			errs = append(errs, fmt.Errorf("approvals[%d]: crd, comparator, digest, and signature are required", i))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+
+	return ret, nil
+}
+
+// changeIdentity is the canonical content of a ChangeDigest.
+type changeIdentity struct {
+	CRD        string `json:"crd"`
+	Comparator string `json:"comparator"`
+	Version    string `json:"version"`
+	Field      string `json:"field"`
+	// Discriminator is omitted when empty so digests of findings without one do not change.
+	Discriminator string `json:"discriminator,omitempty"`
+	OldValue      string `json:"oldValue"`
+	NewValue      string `json:"newValue"`
+
+	// OldSchema and NewSchema are the canonical json of the part of the spec the finding is about, see canonicalSpecAt.
+	OldSchema []json.RawMessage `json:"oldSchema"`
+	NewSchema []json.RawMessage `json:"newSchema"`
+}
+
+// crdIdentity is the part of a CRD spec that names its API, independent of any version.
+type crdIdentity struct {
+	Group string                                        `json:"group"`
+	Names apiextensionsv1.CustomResourceDefinitionNames `json:"names"`
+	Scope apiextensionsv1.ResourceScope                 `json:"scope"`
+}
+
+// ChangeDigest returns a digest of the finding and the old and new schema at the field of the finding.  Any edit to
+// that part of either schema changes the digest.
+func ChangeDigest(finding manifestcomparators.Finding, existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (string, error) {
+	oldSchema, err := canonicalSpecAt(existingCRD, finding.Version, finding.Field)
+	if err != nil {
+		return "", err
+	}
+	newSchema, err := canonicalSpecAt(newCRD, finding.Version, finding.Field)
+	if err != nil {
+		return "", err
+	}
+
+	identity, err := json.Marshal(changeIdentity{
+		CRD:           finding.CRDName,
+		Comparator:    finding.Comparator,
+		Version:       finding.Version,
+		Field:         finding.Field,
+		Discriminator: finding.Discriminator,
+		OldValue:      finding.OldValue,
+		NewValue:      finding.NewValue,
+		OldSchema:     oldSchema,
+		NewSchema:     newSchema,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(identity)
+	return digestPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalSpecAt returns the canonical json of the schema nodes at simpleLocation.  Findings that are not about a
+// field, or about a field that is not declared on this side, are bound to the whole version entry instead, and findings
+// that are not about a version, like the removal of the CRD, to the identity of the CRD.  Only a missing CRD has no
+// content.
+func canonicalSpecAt(crd *apiextensionsv1.CustomResourceDefinition, version, simpleLocation string) ([]json.RawMessage, error) {
+	schemas, err := canonicalSchemasAt(crd, version, simpleLocation)
+	if err != nil || len(schemas) > 0 || crd == nil {
+		return schemas, err
+	}
+
+	var section interface{} = crdIdentity{Group: crd.Spec.Group, Names: crd.Spec.Names, Scope: crd.Spec.Scope}
+	if crdVersion := manifestcomparators.GetVersionByName(crd, version); crdVersion != nil {
+		section = crdVersion
+	}
+	canonical, err := canonicalJSON(section)
+	if err != nil {
+		return nil, err
+	}
+	return []json.RawMessage{canonical}, nil
+}
+
+// canonicalSchemasAt returns the canonical json of every schema node declared at simpleLocation.  There can be more
+// than one when the field is also declared in allOf, anyOf, or oneOf.
+func canonicalSchemasAt(crd *apiextensionsv1.CustomResourceDefinition, version, simpleLocation string) ([]json.RawMessage, error) {
+	ret := []json.RawMessage{}
+	crdVersion := manifestcomparators.GetVersionByName(crd, version)
+	if crdVersion == nil || crdVersion.Schema == nil || len(simpleLocation) == 0 {
+		return ret, nil
+	}
+
+	var errs []error
+	manifestcomparators.SchemaHas(crdVersion.Schema.OpenAPIV3Schema, field.NewPath("^"), field.NewPath("^"), nil,
+		func(s *apiextensionsv1.JSONSchemaProps, fldPath, currLocation *field.Path, _ []*apiextensionsv1.JSONSchemaProps) bool {
+			if currLocation.String() != simpleLocation {
+				return false
+			}
+			canonical, err := canonicalJSON(s)
+			if err != nil {
+				errs = append(errs, err)
+				return false
+			}
+			ret = append(ret, canonical)
+			return false
+		})
+
+	return ret, utilerrors.NewAggregate(errs)
+}
+
+// canonicalJSON round trips obj through an untyped value so that key order and whitespace of raw values like
+// defaults and enums do not depend on how the manifest was written.
+func canonicalJSON(obj interface{}) (json.RawMessage, error) {
+	objBytes, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var untyped interface{}
+	if err := json.Unmarshal(objBytes, &untyped); err != nil {
+		return nil, err
+	}
+	return json.Marshal(untyped)
+}
+
+// Apply turns every error with a verified approval for the exact change into an info that names the trusted key.
+func (l *ApprovalList) Apply(results []manifestcomparators.ComparisonResults, existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition, trustedKeys []TrustedKey) ([]manifestcomparators.ComparisonResults, error) {
+	if l == nil || len(l.Approvals) == 0 {
+		return results, nil
+	}
+
+	errs := []error{}
+	ret := []manifestcomparators.ComparisonResults{}
+	for _, comparisonResult := range results {
+		if len(comparisonResult.Findings) == 0 {
+			// results without findings only have rendered messages, which we cannot match.
+			ret = append(ret, comparisonResult)
+			continue
+		}
+
+		findings := []manifestcomparators.Finding{}
+		for _, finding := range comparisonResult.Findings {
+			if finding.Severity != manifestcomparators.SeverityError {
+				findings = append(findings, finding)
+				continue
+			}
+
+			keyName, err := l.verifiedApprovalFor(finding, existingCRD, newCRD, trustedKeys)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if len(keyName) > 0 {
+				finding.Severity = manifestcomparators.SeverityInfo
+				finding.Message = fmt.Sprintf("approved by signature from %v: %v", keyName, finding.Message)
+			}
+			findings = append(findings, finding)
+		}
+		ret = append(ret, manifestcomparators.NewComparisonResults(comparisonResult.Name, comparisonResult.WhyItMatters, findings))
+	}
+
+	return ret, utilerrors.NewAggregate(errs)
+}
+
+// verifiedApprovalFor returns the name of the trusted key that signed an approval for the finding, or empty if there
+// is no such approval.
+func (l *ApprovalList) verifiedApprovalFor(finding manifestcomparators.Finding, existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition, trustedKeys []TrustedKey) (string, error) {
+	var digest string
+	for _, approval := range l.Approvals {
+		if approval.CRD != finding.CRDName || approval.Comparator != finding.Comparator ||
+			approval.Version != finding.Version || approval.Field != finding.Field {
+			continue
+		}
+
+		if len(digest) == 0 {
+			var err error
+			digest, err = ChangeDigest(finding, existingCRD, newCRD)
+			if err != nil {
+				return "", fmt.Errorf("cannot compute digest for %q: %w", finding.Message, err)
+			}
+		}
+		if strings.TrimSpace(approval.Digest) != digest {
+			continue
+		}
+		if keyName, ok := verifySignature(approval.Signature, []byte(digest), trustedKeys); ok {
+			return keyName, nil
+		}
+	}
+
+	return "", nil
+}
+
+// ApprovalRequests returns an unsigned approval for every error in results.  Approvers sign the digest and fill in
+// the signature.
+func ApprovalRequests(results []manifestcomparators.ComparisonResults, existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (*ApprovalList, error) {
+	ret := &ApprovalList{Approvals: []Approval{}}
+	for _, comparisonResult := range results {
+		for _, finding := range comparisonResult.Findings {
+			if finding.Severity != manifestcomparators.SeverityError {
+				continue
+			}
+			digest, err := ChangeDigest(finding, existingCRD, newCRD)
+			if err != nil {
+				return nil, fmt.Errorf("cannot compute digest for %q: %w", finding.Message, err)
+			}
+			ret.Approvals = append(ret.Approvals, Approval{
+				CRD:        finding.CRDName,
+				Comparator: finding.Comparator,
+				Version:    finding.Version,
+				Field:      finding.Field,
+				Digest:     digest,
+			})
+		}
+	}
+	return ret, nil
+}
+
+func (l *ApprovalList) WriteFile(filename string) error {
+	content, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, content, 0644)
+}
@@ -0,0 +1,245 @@
+package approvals
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
+	"github.com/openshift/crd-schema-checker/pkg/resourceread"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+const crdTemplate = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: schedulers.config.openshift.io
+spec:
+  group: config.openshift.io
+  names:
+    kind: Scheduler
+    plural: schedulers
+  scope: Cluster
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                profile:
+                  description: %q
+                  type: %v
+                policy:
+                  type: %v
+`
+
+func crdWith(t *testing.T, profileDescription, profileType, policyType string) *apiextensionsv1.CustomResourceDefinition {
+	t.Helper()
+	crd, err := resourceread.ReadCustomResourceDefinitionV1([]byte(fmt.Sprintf(crdTemplate, profileDescription, profileType, policyType)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crd
+}
+
+func compare(t *testing.T, existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) []manifestcomparators.ComparisonResults {
+	t.Helper()
+	comparisonResults, err := manifestcomparators.NoDataTypeChange().Compare(existingCRD, newCRD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []manifestcomparators.ComparisonResults{comparisonResults}
+}
+
+func TestApply(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, untrustedKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustedKeys := []TrustedKey{{Name: "approver", Key: publicKey}}
+
+	existingCRD := crdWith(t, "profile", "string", "string")
+	approvedCRD := crdWith(t, "profile", "integer", "integer")
+
+	// approve the change of profile, but not the change of policy.
+	approvalRequests, err := ApprovalRequests(compare(t, existingCRD, approvedCRD), existingCRD, approvedCRD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(approvalRequests.Approvals) != 2 {
+		t.Fatalf("expected two approval requests, got %#v", approvalRequests.Approvals)
+	}
+	var approval Approval
+	for _, approvalRequest := range approvalRequests.Approvals {
+		if approvalRequest.Field == "^.spec.profile" {
+			approval = approvalRequest
+		}
+	}
+
+	tests := []struct {
+		name           string
+		newCRD         *apiextensionsv1.CustomResourceDefinition
+		signingKey     ed25519.PrivateKey
+		expectedErrors []string
+		expectedInfos  []string
+	}{
+		{
+			name:       "approved",
+			newCRD:     approvedCRD,
+			signingKey: privateKey,
+			expectedErrors: []string{
+				"crd/schedulers.config.openshift.io version/v1 data type of field/^.spec.policy may not be changed from string to integer",
+			},
+			expectedInfos: []string{
+				"approved by signature from approver: crd/schedulers.config.openshift.io version/v1 data type of field/^.spec.profile may not be changed from string to integer",
+			},
+		},
+		{
+			name:       "untrusted key",
+			newCRD:     approvedCRD,
+			signingKey: untrustedKey,
+			expectedErrors: []string{
+				"crd/schedulers.config.openshift.io version/v1 data type of field/^.spec.profile may not be changed from string to integer",
+				"crd/schedulers.config.openshift.io version/v1 data type of field/^.spec.policy may not be changed from string to integer",
+			},
+			expectedInfos: []string{},
+		},
+		{
+			name:       "change edited after approval",
+			newCRD:     crdWith(t, "edited", "integer", "integer"),
+			signingKey: privateKey,
+			expectedErrors: []string{
+				"crd/schedulers.config.openshift.io version/v1 data type of field/^.spec.profile may not be changed from string to integer",
+				"crd/schedulers.config.openshift.io version/v1 data type of field/^.spec.policy may not be changed from string to integer",
+			},
+			expectedInfos: []string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signedApproval := approval
+			signedApproval.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(test.signingKey, []byte(approval.Digest)))
+			approvalList := &ApprovalList{Approvals: []Approval{signedApproval}}
+
+			actual, err := approvalList.Apply(compare(t, existingCRD, test.newCRD), existingCRD, test.newCRD, trustedKeys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// properties are walked in map order.
+			sort.Strings(test.expectedErrors)
+			sort.Strings(actual[0].Errors)
+			if !reflect.DeepEqual(test.expectedErrors, actual[0].Errors) {
+				t.Errorf("expected errors %v, got %v", test.expectedErrors, actual[0].Errors)
+			}
+			if !reflect.DeepEqual(test.expectedInfos, actual[0].Infos) {
+				t.Errorf("expected infos %v, got %v", test.expectedInfos, actual[0].Infos)
+			}
+		})
+	}
+}
+
+func TestChangeDigestIgnoresUnrelatedFields(t *testing.T) {
+	existingCRD := crdWith(t, "profile", "string", "string")
+	newCRD := crdWith(t, "profile", "integer", "string")
+	findings := compare(t, existingCRD, newCRD)[0].Findings
+	if len(findings) != 1 {
+		t.Fatalf("expected one finding, got %v", findings)
+	}
+	finding := findings[0]
+
+	digest, err := ChangeDigest(finding, existingCRD, newCRD)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// unrelated fields do not change the digest.
+	otherNewCRD := crdWith(t, "profile", "integer", "boolean")
+	otherDigest, err := ChangeDigest(finding, existingCRD, otherNewCRD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != otherDigest {
+		t.Errorf("expected unrelated changes to keep the digest %v, got %v", digest, otherDigest)
+	}
+}
+
+func TestChangeDigestWithoutSchemaNode(t *testing.T) {
+	existingCRD := crdWith(t, "profile", "string", "string")
+	newCRD := crdWith(t, "profile", "string", "string")
+
+	tests := []struct {
+		name    string
+		finding manifestcomparators.Finding
+		// edit changes the new CRD outside of the schema node of the finding.
+		edit func(crd *apiextensionsv1.CustomResourceDefinition)
+	}{
+		{
+			name:    "version finding is bound to the version entry",
+			finding: manifestcomparators.NewError(newCRD.Name, "v1", "", "version"),
+			edit: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Spec.Versions[0].Served = false
+			},
+		},
+		{
+			name:    "unresolved field is bound to the version entry",
+			finding: manifestcomparators.NewError(newCRD.Name, "v1", "^.spec.removed", "removed"),
+			edit: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Spec.Versions[0].Deprecated = true
+			},
+		},
+		{
+			name:    "crd finding is bound to the identity of the crd",
+			finding: manifestcomparators.NewError(newCRD.Name, "", "", "removed"),
+			edit: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Spec.Scope = apiextensionsv1.NamespaceScoped
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			digest, err := ChangeDigest(test.finding, existingCRD, newCRD)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			editedCRD := newCRD.DeepCopy()
+			test.edit(editedCRD)
+			editedDigest, err := ChangeDigest(test.finding, existingCRD, editedCRD)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if digest == editedDigest {
+				t.Errorf("expected the edit to change the digest %v", digest)
+			}
+		})
+	}
+
+	// a removed CRD is bound to the identity of the existing CRD.
+	removal := manifestcomparators.NewError(existingCRD.Name, "", "", "removed")
+	digest, err := ChangeDigest(removal, existingCRD, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherExistingCRD := existingCRD.DeepCopy()
+	otherExistingCRD.Spec.Group = "operator.openshift.io"
+	otherDigest, err := ChangeDigest(removal, otherExistingCRD, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest == otherDigest {
+		t.Errorf("expected the removal of another CRD to change the digest %v", digest)
+	}
+}
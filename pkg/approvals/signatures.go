@@ -0,0 +1,256 @@
+package approvals
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"strings"
+)
+
+const (
+	// SSHSignatureNamespace is the namespace that `ssh-keygen -Y sign -n` must use for approvals.
+	SSHSignatureNamespace = "crd-schema-checker"
+
+	sshKeyTypeED25519   = "ssh-ed25519"
+	sshSignatureMagic   = "SSHSIG"
+	sshSignatureVersion = 1
+)
+
+// TrustedKey is a public key that is allowed to sign approvals.
+type TrustedKey struct {
+	// Name is the comment of an authorized_keys line or the fingerprint of the key.
+	Name string
+	Key  ed25519.PublicKey
+}
+
+func ReadTrustedKeysFile(filename string) ([]TrustedKey, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read trusted keys file: %w", err)
+	}
+	return ReadTrustedKeys(content)
+}
+
+// ReadTrustedKeys reads ed25519 public keys as authorized_keys lines (ssh-ed25519 AAAA... name) and as PEM encoded
+// PUBLIC KEY blocks.  Other key types are rejected instead of ignored so that a trusted approver is never silently
+// dropped.
+func ReadTrustedKeys(content []byte) ([]TrustedKey, error) {
+	ret := []TrustedKey{}
+
+	inPEM := false
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "-----BEGIN "):
+			inPEM = true
+			continue
+		case strings.HasPrefix(line, "-----END "):
+			inPEM = false
+			continue
+		case inPEM, len(line) == 0, strings.HasPrefix(line, "#"):
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if fields[0] != sshKeyTypeED25519 || len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: only %v keys are supported", i+1, sshKeyTypeED25519)
+		}
+		wireKey, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		key, err := parseSSHPublicKey(wireKey)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		name := strings.Join(fields[2:], " ")
+		if len(name) == 0 {
+			name = fingerprint(key)
+		}
+		ret = append(ret, TrustedKey{Name: name, Key: key})
+	}
+
+	rest := content
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+		}
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("only ed25519 keys are supported, got %T", publicKey)
+		}
+		ret = append(ret, TrustedKey{Name: fingerprint(key), Key: key})
+	}
+
+	return ret, nil
+}
+
+// fingerprint is the same SHA256 fingerprint that ssh-keygen -l prints.
+func fingerprint(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(marshalSSHPublicKey(key))
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// verifySignature returns the name of the trusted key that produced signature over message.
+func verifySignature(signature string, message []byte, trustedKeys []TrustedKey) (string, bool) {
+	signature = strings.TrimSpace(signature)
+	if strings.HasPrefix(signature, "-----BEGIN SSH SIGNATURE-----") {
+		return verifySSHSignature(signature, message, trustedKeys)
+	}
+
+	rawSignature, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return "", false
+	}
+	for _, trustedKey := range trustedKeys {
+		if ed25519.Verify(trustedKey.Key, message, rawSignature) {
+			return trustedKey.Name, true
+		}
+	}
+	return "", false
+}
+
+// verifySSHSignature verifies an armored signature from `ssh-keygen -Y sign` as described in
+// https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+func verifySSHSignature(signature string, message []byte, trustedKeys []TrustedKey) (string, bool) {
+	block, _ := pem.Decode([]byte(signature))
+	if block == nil || block.Type != "SSH SIGNATURE" {
+		return "", false
+	}
+
+	r := &sshReader{buf: block.Bytes}
+	magic := r.bytes(len(sshSignatureMagic))
+	version := r.uint32()
+	wireKey := r.string()
+	namespace := r.string()
+	reserved := r.string()
+	hashAlgorithm := r.string()
+	wireSignature := r.string()
+	if r.err != nil || string(magic) != sshSignatureMagic || version != sshSignatureVersion || string(namespace) != SSHSignatureNamespace {
+		return "", false
+	}
+
+	key, err := parseSSHPublicKey(wireKey)
+	if err != nil {
+		return "", false
+	}
+	signatureReader := &sshReader{buf: wireSignature}
+	signatureType := signatureReader.string()
+	rawSignature := signatureReader.string()
+	if signatureReader.err != nil || string(signatureType) != sshKeyTypeED25519 {
+		return "", false
+	}
+
+	var h hash.Hash
+	switch string(hashAlgorithm) {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return "", false
+	}
+	h.Write(message)
+
+	signed := &bytes.Buffer{}
+	signed.WriteString(sshSignatureMagic)
+	writeSSHString(signed, namespace)
+	writeSSHString(signed, reserved)
+	writeSSHString(signed, hashAlgorithm)
+	writeSSHString(signed, h.Sum(nil))
+
+	for _, trustedKey := range trustedKeys {
+		if !trustedKey.Key.Equal(key) {
+			continue
+		}
+		if ed25519.Verify(key, signed.Bytes(), rawSignature) {
+			return trustedKey.Name, true
+		}
+	}
+	return "", false
+}
+
+// parseSSHPublicKey parses the wire format of an ssh-ed25519 public key.
+func parseSSHPublicKey(wireKey []byte) (ed25519.PublicKey, error) {
+	r := &sshReader{buf: wireKey}
+	keyType := r.string()
+	key := r.string()
+	if r.err != nil {
+		return nil, fmt.Errorf("cannot parse ssh public key: %w", r.err)
+	}
+	if string(keyType) != sshKeyTypeED25519 {
+		return nil, fmt.Errorf("only %v keys are supported, got %q", sshKeyTypeED25519, keyType)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("ed25519 key must be %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+func marshalSSHPublicKey(key ed25519.PublicKey) []byte {
+	buf := &bytes.Buffer{}
+	writeSSHString(buf, []byte(sshKeyTypeED25519))
+	writeSSHString(buf, key)
+	return buf.Bytes()
+}
+
+func writeSSHString(buf *bytes.Buffer, value []byte) {
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(value)))
+	buf.Write(length)
+	buf.Write(value)
+}
+
+// sshReader reads the length prefixed values of the ssh wire format.  The first failure is kept in err and every later
+// read returns nil.
+type sshReader struct {
+	buf []byte
+	err error
+}
+
+func (r *sshReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.buf) < n {
+		r.err = errors.New("unexpected end of data")
+		return nil
+	}
+	ret := r.buf[:n]
+	r.buf = r.buf[n:]
+	return ret
+}
+
+func (r *sshReader) uint32() uint32 {
+	value := r.bytes(4)
+	if value == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(value)
+}
+
+func (r *sshReader) string() []byte {
+	length := r.uint32()
+	if r.err != nil {
+		return nil
+	}
+	return r.bytes(int(length))
+}
@@ -0,0 +1,124 @@
+package approvals
+
+import (
+	"testing"
+)
+
+// the fixtures were created with
+//
+//	ssh-keygen -t ed25519 -C approver@example.com -f key
+//	ssh-keygen -Y sign -n crd-schema-checker -f key message
+//	openssl genpkey -algorithm ed25519 -out ed.pem
+//	openssl pkeyutl -sign -inkey ed.pem -rawin -in message | base64
+const (
+	signedMessage = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+	trustedKeysFile = `
+# approvers
+ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBd0zwEIjkdJTdMTTyEaLRQ52flRlycDoxgFoHe36/Z7 approver@example.com
+-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEApnKlY3Hp28LL0JYmE0ihG4lxTXb3fK/UwVAG4wuDPAE=
+-----END PUBLIC KEY-----
+`
+
+	sshSignature = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgF3TPAQiOR0lN0xNPIRotFDnZ+V
+GXJwOjGAWgd7fr9nsAAAASY3JkLXNjaGVtYS1jaGVja2VyAAAAAAAAAAZzaGE1MTIAAABT
+AAAAC3NzaC1lZDI1NTE5AAAAQALJcNj1n+XsKtHzdUIR1FFosV2dXNnrJAy8I8hM9JgQry
+3jRxO5TSXb+xm4ZSg+jbocgzAuLLApGhF2wAGHfgw=
+-----END SSH SIGNATURE-----
+`
+
+	ed25519Signature = "aB/wnlZbdvdBMTm9klEOU/HqE6yZVZiPivt5yX+j11EQe69x3B8HuzGrOp//bAGZJvm7f5EVjjGWB/+Nwkl7CQ=="
+)
+
+func TestReadTrustedKeys(t *testing.T) {
+	trustedKeys, err := ReadTrustedKeys([]byte(trustedKeysFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trustedKeys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(trustedKeys))
+	}
+	if trustedKeys[0].Name != "approver@example.com" {
+		t.Errorf("expected the comment as name, got %q", trustedKeys[0].Name)
+	}
+	if expected := "SHA256:"; trustedKeys[1].Name[:len(expected)] != expected {
+		t.Errorf("expected a fingerprint as name, got %q", trustedKeys[1].Name)
+	}
+
+	if _, err := ReadTrustedKeys([]byte("ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQ== rsa@example.com\n")); err == nil {
+		t.Errorf("expected rsa keys to be rejected")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	trustedKeys, err := ReadTrustedKeys([]byte(trustedKeysFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		signature    string
+		message      string
+		trustedKeys  []TrustedKey
+		expectedName string
+		expectedOK   bool
+	}{
+		{
+			name:         "ssh signature",
+			signature:    sshSignature,
+			message:      signedMessage,
+			trustedKeys:  trustedKeys,
+			expectedName: "approver@example.com",
+			expectedOK:   true,
+		},
+		{
+			name:         "ed25519 signature",
+			signature:    ed25519Signature,
+			message:      signedMessage,
+			trustedKeys:  trustedKeys,
+			expectedName: trustedKeys[1].Name,
+			expectedOK:   true,
+		},
+		{
+			name:        "ssh signature of other message",
+			signature:   sshSignature,
+			message:     signedMessage + "0",
+			trustedKeys: trustedKeys,
+		},
+		{
+			name:        "ed25519 signature of other message",
+			signature:   ed25519Signature,
+			message:     signedMessage + "0",
+			trustedKeys: trustedKeys,
+		},
+		{
+			name:        "ssh signature of untrusted key",
+			signature:   sshSignature,
+			message:     signedMessage,
+			trustedKeys: trustedKeys[1:],
+		},
+		{
+			name:        "ed25519 signature of untrusted key",
+			signature:   ed25519Signature,
+			message:     signedMessage,
+			trustedKeys: trustedKeys[:1],
+		},
+		{
+			name:        "garbage",
+			signature:   "not a signature",
+			message:     signedMessage,
+			trustedKeys: trustedKeys,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, ok := verifySignature(test.signature, []byte(test.message), test.trustedKeys)
+			if ok != test.expectedOK || name != test.expectedName {
+				t.Errorf("expected %q, %v, got %q, %v", test.expectedName, test.expectedOK, name, ok)
+			}
+		})
+	}
+}
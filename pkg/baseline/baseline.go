@@ -0,0 +1,146 @@
+package baseline
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Baseline is the content of a baseline file: the findings that were already present when a comparator was adopted.
+type Baseline struct {
+	Findings []Entry `yaml:"findings"`
+}
+
+// Entry is the identity of a finding.  It deliberately excludes the message so that rewording a message does not
+// resurface every baselined finding.
+type Entry struct {
+	Comparator string                       `yaml:"comparator"`
+	Severity   manifestcomparators.Severity `yaml:"severity"`
+	CRD        string                       `yaml:"crd"`
+	Version    string                       `yaml:"version,omitempty"`
+	Field      string                       `yaml:"field,omitempty"`
+	// Discriminator tells apart findings of one comparator on the same field.
+	Discriminator string `yaml:"discriminator,omitempty"`
+	OldValue      string `yaml:"oldValue,omitempty"`
+	NewValue      string `yaml:"newValue,omitempty"`
+}
+
+func EntryFor(finding manifestcomparators.Finding) Entry {
+	return Entry{
+		Comparator:    finding.Comparator,
+		Severity:      finding.Severity,
+		CRD:           finding.CRDName,
+		Version:       finding.Version,
+		Field:         finding.Field,
+		Discriminator: finding.Discriminator,
+		OldValue:      finding.OldValue,
+		NewValue:      finding.NewValue,
+	}
+}
+
+func ReadBaselineFile(filename string) (*Baseline, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read baseline file: %w", err)
+	}
+	return ReadBaseline(content)
+}
+
+// ReadBaselineFileIfExists returns an empty baseline when filename does not exist, so a baseline can be written for
+// the first time.
+func ReadBaselineFileIfExists(filename string) (*Baseline, error) {
+	if _, err := os.Stat(filename); errors.Is(err, fs.ErrNotExist) {
+		return &Baseline{}, nil
+	}
+	return ReadBaselineFile(filename)
+}
+
+func ReadBaseline(content []byte) (*Baseline, error) {
+	ret := &Baseline{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, fmt.Errorf("cannot decode baseline: %w", err)
+	}
+	return ret, nil
+}
+
+func (b *Baseline) WriteFile(filename string) error {
+	content, err := yaml.Marshal(b)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, content, 0644); err != nil {
+		return fmt.Errorf("cannot write baseline file: %w", err)
+	}
+	return nil
+}
+
+// Record replaces the entries of crdNames with the findings in results.  Entries of other CRDs are kept, so one file
+// can hold the baseline of many CRDs checked one at a time.
+func (b *Baseline) Record(crdNames []string, results []manifestcomparators.ComparisonResults) {
+	recordedCRDs := sets.NewString(crdNames...)
+	entries := []Entry{}
+	for _, entry := range b.Findings {
+		if !recordedCRDs.Has(entry.CRD) {
+			entries = append(entries, entry)
+		}
+	}
+
+	seen := map[Entry]bool{}
+	for _, comparisonResult := range results {
+		for _, finding := range comparisonResult.Findings {
+			entry := EntryFor(finding)
+			if seen[entry] {
+				continue
+			}
+			seen[entry] = true
+			entries = append(entries, entry)
+		}
+	}
+
+	// sort so the file only changes when the findings change.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entryKey(entries[i]) < entryKey(entries[j])
+	})
+	b.Findings = entries
+}
+
+func entryKey(entry Entry) string {
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s", entry.CRD, entry.Comparator, entry.Version, entry.Field, entry.Discriminator, entry.Severity, entry.OldValue, entry.NewValue)
+}
+
+// Filter removes every finding that is in the baseline.
+func (b *Baseline) Filter(results []manifestcomparators.ComparisonResults) []manifestcomparators.ComparisonResults {
+	if b == nil || len(b.Findings) == 0 {
+		return results
+	}
+
+	known := map[Entry]bool{}
+	for _, entry := range b.Findings {
+		known[entry] = true
+	}
+
+	ret := []manifestcomparators.ComparisonResults{}
+	for _, comparisonResult := range results {
+		if len(comparisonResult.Findings) == 0 {
+			// results without findings only have rendered messages, which we cannot match.
+			ret = append(ret, comparisonResult)
+			continue
+		}
+		findings := []manifestcomparators.Finding{}
+		for _, finding := range comparisonResult.Findings {
+			if known[EntryFor(finding)] {
+				continue
+			}
+			findings = append(findings, finding)
+		}
+		ret = append(ret, manifestcomparators.NewComparisonResults(comparisonResult.Name, comparisonResult.WhyItMatters, findings))
+	}
+
+	return ret
+}
@@ -0,0 +1,92 @@
+package baseline
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
+)
+
+const (
+	schedulers = "schedulers.config.openshift.io"
+	widgets    = "widgets.example.com"
+)
+
+func TestRecord(t *testing.T) {
+	knownFindings := &Baseline{
+		Findings: []Entry{
+			{Comparator: "NoBools", Severity: manifestcomparators.SeverityError, CRD: widgets, Version: "v1", Field: "^.spec.enabled"},
+			{Comparator: "NoBools", Severity: manifestcomparators.SeverityError, CRD: schedulers, Version: "v1", Field: "^.spec.fixed"},
+		},
+	}
+
+	knownFindings.Record([]string{schedulers}, []manifestcomparators.ComparisonResults{
+		manifestcomparators.NewComparisonResults("NoFieldRemoval", "", []manifestcomparators.Finding{
+			manifestcomparators.NewError(schedulers, "v1", "^.spec.removed", "removed"),
+			manifestcomparators.NewError(schedulers, "v1", "^.spec.removed", "reported twice"),
+		}),
+		manifestcomparators.NewComparisonResults("NoDataTypeChange", "", []manifestcomparators.Finding{
+			manifestcomparators.NewError(schedulers, "v1", "^.spec.profile", "changed").WithValues("string", "integer"),
+		}),
+	})
+
+	expected := []Entry{
+		{Comparator: "NoDataTypeChange", Severity: manifestcomparators.SeverityError, CRD: schedulers, Version: "v1", Field: "^.spec.profile", OldValue: "string", NewValue: "integer"},
+		{Comparator: "NoFieldRemoval", Severity: manifestcomparators.SeverityError, CRD: schedulers, Version: "v1", Field: "^.spec.removed"},
+		{Comparator: "NoBools", Severity: manifestcomparators.SeverityError, CRD: widgets, Version: "v1", Field: "^.spec.enabled"},
+	}
+	if !reflect.DeepEqual(expected, knownFindings.Findings) {
+		t.Errorf("expected\n%v\n, got\n%v", expected, knownFindings.Findings)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	knownFindings := &Baseline{
+		Findings: []Entry{
+			{Comparator: "NoFieldRemoval", Severity: manifestcomparators.SeverityError, CRD: schedulers, Version: "v1", Field: "^.spec.removed"},
+			{Comparator: "NoDataTypeChange", Severity: manifestcomparators.SeverityError, CRD: schedulers, Version: "v1", Field: "^.spec.profile", OldValue: "string", NewValue: "integer"},
+		},
+	}
+
+	actual := knownFindings.Filter([]manifestcomparators.ComparisonResults{
+		manifestcomparators.NewComparisonResults("NoFieldRemoval", "", []manifestcomparators.Finding{
+			manifestcomparators.NewError(schedulers, "v1", "^.spec.removed", "the message is not part of the identity"),
+			manifestcomparators.NewError(schedulers, "v2", "^.spec.removed", "other version"),
+			manifestcomparators.NewWarning(schedulers, "v1", "^.spec.removed", "other severity"),
+		}),
+		manifestcomparators.NewComparisonResults("NoDataTypeChange", "", []manifestcomparators.Finding{
+			manifestcomparators.NewError(schedulers, "v1", "^.spec.profile", "known change").WithValues("string", "integer"),
+			manifestcomparators.NewError(schedulers, "v1", "^.spec.profile", "new change").WithValues("string", "boolean"),
+		}),
+	})
+
+	if expected := []string{"other version"}; !reflect.DeepEqual(expected, actual[0].Errors) {
+		t.Errorf("expected errors %v, got %v", expected, actual[0].Errors)
+	}
+	if expected := []string{"other severity"}; !reflect.DeepEqual(expected, actual[0].Warnings) {
+		t.Errorf("expected warnings %v, got %v", expected, actual[0].Warnings)
+	}
+	if expected := []string{"new change"}; !reflect.DeepEqual(expected, actual[1].Errors) {
+		t.Errorf("expected errors %v, got %v", expected, actual[1].Errors)
+	}
+}
+
+func TestFilterDiscriminator(t *testing.T) {
+	knownFindings := &Baseline{}
+	knownFindings.Record([]string{schedulers}, []manifestcomparators.ComparisonResults{
+		manifestcomparators.NewComparisonResults("NoValidationTightening", "", []manifestcomparators.Finding{
+			manifestcomparators.NewError(schedulers, "v1", "^.spec.name", "maxLength").WithValues("10", "5").WithDiscriminator("maxLength"),
+		}),
+	})
+
+	actual := knownFindings.Filter([]manifestcomparators.ComparisonResults{
+		manifestcomparators.NewComparisonResults("NoValidationTightening", "", []manifestcomparators.Finding{
+			manifestcomparators.NewError(schedulers, "v1", "^.spec.name", "known maxLength").WithValues("10", "5").WithDiscriminator("maxLength"),
+			manifestcomparators.NewError(schedulers, "v1", "^.spec.name", "new minLength").WithValues("10", "5").WithDiscriminator("minLength"),
+		}),
+	})
+
+	if expected := []string{"new minLength"}; !reflect.DeepEqual(expected, actual[0].Errors) {
+		t.Errorf("expected errors %v, got %v", expected, actual[0].Errors)
+	}
+}
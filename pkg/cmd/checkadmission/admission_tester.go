@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
-	"strings"
 	"testing"
 	"time"
 
@@ -21,6 +19,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/openshift/crd-schema-checker/pkg/admissionevaluator"
 	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
 	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/client-go/rest"
@@ -88,9 +87,7 @@ func (tc *admissionComparatorTest) Test(t *testing.T) {
 	}
 	actual.Request = nil
 
-	actualResults := []manifestcomparators.ComparisonResults{}
 	actualErrors := []error{}
-	comparatorNameToResults := map[string]manifestcomparators.ComparisonResults{}
 
 	if actual.Response.UID != admissionReview.Request.UID {
 		t.Fatalf("mismatch of UID: sent %v, got %v", admissionReview.Request.UID, actual.Response.UID)
@@ -105,65 +102,42 @@ func (tc *admissionComparatorTest) Test(t *testing.T) {
 		t.Log(string(responseJSON))
 	}
 
+	// the causes only carry the errors, every finding is in the audit annotation.
 	if actual.Response.Result != nil && actual.Response.Result.Details != nil {
 		for _, cause := range actual.Response.Result.Details.Causes {
 			t.Log(cause.Message)
-			name := string(cause.Type)
-			if name == "EvaluationError" {
+			if cause.Type == "EvaluationError" {
 				actualErrors = append(actualErrors, fmt.Errorf("%s", cause.Message))
-				continue
 			}
-			results := comparatorNameToResults[name]
-			results.Name = name
-			results.Errors = append(results.Errors, cause.Message)
-			comparatorNameToResults[name] = results
-		}
-	} else {
-		switch {
-		case actual.Response.Result == nil:
-			t.Logf("got no response.result")
-		case actual.Response.Result.Details == nil:
-			t.Logf("got no response.result.details, but result: %v", actual.Response.Result)
 		}
-
 	}
 
 	t.Logf("got %d warnings", len(actual.Response.Warnings))
 	for _, warning := range actual.Response.Warnings {
 		t.Log(warning)
-		if strings.HasPrefix(warning, "fyi: ") {
-			parts := strings.SplitN(warning, ":", 3)
-
-			// The warning formatting adds space and quotes, need to undo that.
-			name, err := strconv.Unquote(strings.TrimSpace(parts[1]))
-			if err != nil {
-				t.Errorf("failed to unquote %q, has the response change?: %v", parts[1], err)
-			}
+	}
 
-			results := comparatorNameToResults[name]
-			results.Name = name
-			results.Infos = append(results.Infos, strings.TrimSpace(parts[2]))
-			comparatorNameToResults[name] = results
-			continue
+	findings := []manifestcomparators.Finding{}
+	if findingsJSON, ok := actual.Response.AuditAnnotations[admissionevaluator.FindingsAuditAnnotation]; ok {
+		if err := json.Unmarshal([]byte(findingsJSON), &findings); err != nil {
+			t.Fatalf("failed to decode findings, has the response changed?: %v", err)
 		}
-		parts := strings.SplitN(warning, ":", 2)
-
-		// We quote the comparator name when returning the warnings.
-		name, err := strconv.Unquote(parts[0])
-		if err != nil {
-			t.Errorf("failed to unquote %q, has the response change?: %v", parts[1], err)
+	}
+	comparatorNames := []string{}
+	comparatorNameToFindings := map[string][]manifestcomparators.Finding{}
+	for _, finding := range findings {
+		if _, ok := comparatorNameToFindings[finding.Comparator]; !ok {
+			comparatorNames = append(comparatorNames, finding.Comparator)
 		}
-
-		results := comparatorNameToResults[name]
-		results.Name = name
-		results.Warnings = append(results.Warnings, strings.TrimSpace(parts[1]))
-		comparatorNameToResults[name] = results
+		comparatorNameToFindings[finding.Comparator] = append(comparatorNameToFindings[finding.Comparator], finding)
 	}
-
-	for name := range comparatorNameToResults {
-		results := comparatorNameToResults[name]
-		actualResults = append(actualResults, results)
+	actualResults := []manifestcomparators.ComparisonResults{}
+	for _, name := range comparatorNames {
+		actualResults = append(actualResults, manifestcomparators.NewComparisonResults(name, "", comparatorNameToFindings[name]))
 	}
 
+	// the server runs every default comparator, only check the ones the test is about.
+	actualResults = tc.ComparatorTest.ResultsOfListedComparators(actualResults)
 	tc.ComparatorTest.Test(t, actualResults, actualErrors)
+	tc.ComparatorTest.TestFindings(t, actualResults)
 }
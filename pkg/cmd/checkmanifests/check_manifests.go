@@ -1,17 +1,21 @@
 package checkmanifests
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/openshift/crd-schema-checker/pkg/approvals"
+	"github.com/openshift/crd-schema-checker/pkg/baseline"
 	"github.com/openshift/crd-schema-checker/pkg/cmd/options"
 
 	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
 	"github.com/openshift/crd-schema-checker/pkg/resourceread"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/klog/v2"
 )
@@ -19,6 +23,16 @@ import (
 type CheckManifestOptions struct {
 	ExistingCRDFile string
 	NewCRDFile      string
+	Output          string
+
+	// ExistingGitRef and NewGitRef read the CRD files as of a revision of the git repository of the current directory.
+	ExistingGitRef string
+	NewGitRef      string
+
+	FailOnStaleExceptions bool
+	ApprovalRequestsFile  string
+	BaselineFile          string
+	WriteBaselineFile     string
 
 	ComparatorOptions *options.ComparatorOptions
 
@@ -63,14 +77,30 @@ func NewCheckManifestsCommand(streams genericclioptions.IOStreams) *cobra.Comman
 
 func (o *CheckManifestOptions) AddFlags(fs *pflag.FlagSet) {
 	o.ComparatorOptions.AddFlags(fs)
-	fs.StringVar(&o.ExistingCRDFile, "existing-crd-filename", o.ExistingCRDFile, "file of existing CRD")
-	fs.StringVar(&o.NewCRDFile, "new-crd-filename", o.NewCRDFile, "file of new CRD")
+	fs.StringVar(&o.ExistingCRDFile, "existing-crd-filename", o.ExistingCRDFile, "file or directory of existing CRDs. Files may hold multiple yaml documents.")
+	fs.StringVar(&o.NewCRDFile, "new-crd-filename", o.NewCRDFile, "file or directory of new CRDs. Files may hold multiple yaml documents.")
+	fs.StringVar(&o.ExistingGitRef, "existing-git-ref", o.ExistingGitRef, "read the existing CRDs as of this git revision. --existing-crd-filename defaults to --new-crd-filename.")
+	fs.StringVar(&o.NewGitRef, "new-git-ref", o.NewGitRef, "read the new CRDs as of this git revision instead of the working tree")
+	fs.BoolVar(&o.FailOnStaleExceptions, "fail-on-stale-exceptions", o.FailOnStaleExceptions, "report exceptions that are expired, match no finding, or name a CRD that is not checked as errors instead of warnings")
+	fs.StringVar(&o.ApprovalRequestsFile, "write-approval-requests", o.ApprovalRequestsFile, "write an unsigned approval with the digest of every remaining error to this file")
+	fs.StringVar(&o.BaselineFile, "baseline", o.BaselineFile, "file of known findings. Only findings that are not in the baseline are reported.")
+	fs.StringVar(&o.WriteBaselineFile, "write-baseline", o.WriteBaselineFile, "record the current findings of the compared CRD in this file, keeping the entries of other CRDs")
+	fs.StringVarP(&o.Output, "output", "o", o.Output, fmt.Sprintf("output format, one of: %v. Defaults to human readable text.", strings.Join(knownOutputFormats(), ", ")))
 }
 
 func (o *CheckManifestOptions) Validate() error {
 	if len(o.NewCRDFile) == 0 {
 		return fmt.Errorf("--new-crd-filename is required")
 	}
+	if _, ok := reportWriters[o.Output]; !ok && o.Output != OutputFormatText {
+		return fmt.Errorf("--output must be one of: %v", strings.Join(knownOutputFormats(), ", "))
+	}
+	if o.FailOnStaleExceptions && len(o.ComparatorOptions.ExceptionsFile) == 0 {
+		return fmt.Errorf("--fail-on-stale-exceptions requires --exceptions-file")
+	}
+	if len(o.BaselineFile) > 0 && len(o.WriteBaselineFile) > 0 {
+		return fmt.Errorf("--baseline and --write-baseline are mutually exclusive")
+	}
 	if err := o.ComparatorOptions.Validate(); err != nil {
 		return err
 	}
@@ -80,76 +110,256 @@ func (o *CheckManifestOptions) Validate() error {
 // Complete fills in missing values before command execution.
 func (o *CheckManifestOptions) Complete() (*CheckManifestConfig, error) {
 	ret := &CheckManifestConfig{
-		IOStreams: o.IOStreams,
+		Output:               o.Output,
+		ApprovalRequestsFile: o.ApprovalRequestsFile,
+		IOStreams:            o.IOStreams,
 	}
 
-	if len(o.ExistingCRDFile) > 0 {
-		content, err := os.ReadFile(o.ExistingCRDFile)
-		if err != nil {
-			return nil, fmt.Errorf("cannot read existing CRD manifest: %w", err)
+	existingCRDFile := o.ExistingCRDFile
+	findMovedCRDs := false
+	if len(existingCRDFile) == 0 && len(o.ExistingGitRef) > 0 {
+		// CRDs are paired by name, so files that moved within the new path are still found, and CRDs that moved into
+		// it are looked up in the rest of the repository.
+		existingCRDFile = o.NewCRDFile
+		findMovedCRDs = true
+	}
+	existingManifests := []resourceread.CustomResourceDefinitionManifest{}
+	if len(existingCRDFile) > 0 {
+		var err error
+		existingManifests, err = readManifests(o.ExistingGitRef, existingCRDFile)
+		if findMovedCRDs && errors.Is(err, resourceread.ErrNotInRevision) {
+			// the new path was renamed or added since the revision.
+			existingManifests, err = []resourceread.CustomResourceDefinitionManifest{}, nil
 		}
-		crd, err := resourceread.ReadCustomResourceDefinitionV1(content)
 		if err != nil {
-			return nil, fmt.Errorf("cannot decode CRD manifest: %w", err)
+			return nil, fmt.Errorf("cannot read existing CRD manifests: %w", err)
 		}
-		ret.ExistingCRD = crd
 	}
-
-	content, err := os.ReadFile(o.NewCRDFile)
+	newManifests, err := readManifests(o.NewGitRef, o.NewCRDFile)
 	if err != nil {
-		return nil, fmt.Errorf("cannot read new CRD manifest: %w", err)
+		return nil, fmt.Errorf("cannot read new CRD manifests: %w", err)
+	}
+	if len(newManifests) == 0 {
+		return nil, fmt.Errorf("no CRDs found in %v", o.NewCRDFile)
 	}
-	crd, err := resourceread.ReadCustomResourceDefinitionV1(content)
+	if findMovedCRDs {
+		movedManifests, err := findMovedManifests(o.ExistingGitRef, existingManifests, newManifests)
+		if err != nil {
+			return nil, fmt.Errorf("cannot find moved CRD manifests, pass their path as of %v as --existing-crd-filename: %w", o.ExistingGitRef, err)
+		}
+		existingManifests = append(existingManifests, movedManifests...)
+	}
+	ret.CRDPairs, err = pairCRDs(existingManifests, newManifests)
 	if err != nil {
-		return nil, fmt.Errorf("cannot decode CRD manifest: %w", err)
+		return nil, err
+	}
+
+	if len(o.BaselineFile) > 0 {
+		knownFindings, err := baseline.ReadBaselineFile(o.BaselineFile)
+		if err != nil {
+			return nil, err
+		}
+		ret.Baseline = knownFindings
+	}
+	if len(o.WriteBaselineFile) > 0 {
+		// entries for other CRDs are kept, so start from the current content.
+		knownFindings, err := baseline.ReadBaselineFileIfExists(o.WriteBaselineFile)
+		if err != nil {
+			return nil, err
+		}
+		ret.Baseline = knownFindings
+		ret.WriteBaselineFile = o.WriteBaselineFile
 	}
-	ret.NewCRD = crd
 
 	comparatorConfig, err := o.ComparatorOptions.Complete()
 	if err != nil {
 		return nil, err
 	}
 	ret.ComparatorConfig = comparatorConfig
+	ret.ComparatorConfig.ReportStaleExceptions = true
+	ret.ComparatorConfig.FailOnStaleExceptions = o.FailOnStaleExceptions
 
 	return ret, nil
 }
 
+// findMovedManifests looks up the new CRDs that are not in the existing manifests anywhere in the repository as of
+// gitRef, so CRDs that moved into the new path are compared instead of checked as creates.
+func findMovedManifests(gitRef string, existingManifests, newManifests []resourceread.CustomResourceDefinitionManifest) ([]resourceread.CustomResourceDefinitionManifest, error) {
+	missingNames := sets.NewString()
+	for _, manifest := range newManifests {
+		missingNames.Insert(manifest.CRD.Name)
+	}
+	for _, manifest := range existingManifests {
+		missingNames.Delete(manifest.CRD.Name)
+	}
+	if missingNames.Len() == 0 {
+		return nil, nil
+	}
+	return resourceread.FindCustomResourceDefinitionManifestsInGit(gitRef, missingNames)
+}
+
+// readManifests reads the CRDs in path from the working tree or, when gitRef is set, as of that revision.
+func readManifests(gitRef, path string) ([]resourceread.CustomResourceDefinitionManifest, error) {
+	if len(gitRef) > 0 {
+		return resourceread.ReadCustomResourceDefinitionManifestsFromGit(gitRef, path)
+	}
+	return resourceread.ReadCustomResourceDefinitionManifests(path)
+}
+
 type CheckManifestConfig struct {
-	ExistingCRD *apiextensionsv1.CustomResourceDefinition
-	NewCRD      *apiextensionsv1.CustomResourceDefinition
+	// CRDPairs are the CRDs to check, paired by name.
+	CRDPairs []CRDPair
 
 	ComparatorConfig *options.ComparatorConfig
 
+	Output string
+	// ApprovalRequestsFile is where to write the approvals that would allow the remaining errors.
+	ApprovalRequestsFile string
+
+	// Baseline holds the known findings that are not reported.
+	Baseline *baseline.Baseline
+	// WriteBaselineFile is where to record the current findings before they are filtered by Baseline.
+	WriteBaselineFile string
+
 	IOStreams genericclioptions.IOStreams
 }
 
+// CRDResults are the results of checking one CRDPair.
+type CRDResults struct {
+	CRDPair
+
+	Results []manifestcomparators.ComparisonResults
+	// Errs are the errors of comparators that could not evaluate this CRD.
+	Errs []error
+}
+
+// Failed is true when any comparator reported an error or could not be evaluated.
+func (r *CRDResults) Failed() bool {
+	return len(r.Errs) > 0 || hasErrors(r.Results)
+}
+
 // Run contains the logic of the render command.
-func (c *CheckManifestConfig) Run() ([]manifestcomparators.ComparisonResults, bool, error) {
+func (c *CheckManifestConfig) Run() ([]*CRDResults, bool, error) {
 	failed := false
 
-	comparisonResults, errs := c.ComparatorConfig.ComparatorRegistry.Compare(c.ExistingCRD, c.NewCRD, c.ComparatorConfig.ComparatorNames...)
+	allResults := []*CRDResults{}
+	crdNames := []string{}
+	for _, pair := range c.CRDPairs {
+		crdResults := &CRDResults{CRDPair: pair}
+		crdResults.Results, crdResults.Errs = c.ComparatorConfig.Compare(pair.ExistingCRD, pair.NewCRD)
+		allResults = append(allResults, crdResults)
+		crdNames = append(crdNames, pair.Name())
+	}
+	// exceptions for CRDs that are in none of the manifests can only be found once every pair was compared.
+	runResults := c.ComparatorConfig.StaleExceptionsForMissingCRDs(crdNames)
+
+	// errs are the errors that do not belong to a single CRD.
+	errs := []error{}
+	if len(c.WriteBaselineFile) > 0 {
+		if err := c.writeBaseline(allResults); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, crdResults := range allResults {
+		crdResults.Results = c.Baseline.Filter(crdResults.Results)
+	}
+	if len(c.ApprovalRequestsFile) > 0 {
+		if err := c.writeApprovalRequests(allResults); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if writeReport, ok := reportWriters[c.Output]; ok {
+		failed = len(errs) > 0 || hasErrors(runResults)
+		for _, crdResults := range allResults {
+			failed = failed || crdResults.Failed()
+		}
+		report := c.newReport(runResults, allResults, errs, failed)
+		if err := writeReport(c.IOStreams.Out, report); err != nil {
+			errs = append(errs, err)
+		}
+		return allResults, failed, utilerrors.NewAggregate(append(errs, allErrs(allResults)...))
+	}
+
 	if len(errs) > 0 {
 		failed = true
 		for _, err := range errs {
 			fmt.Fprintf(c.IOStreams.ErrOut, "Error during evalutions: %v\n", err)
 		}
 	}
-	for _, comparisonResult := range comparisonResults {
-		for _, msg := range comparisonResult.Errors {
+	for _, crdResults := range allResults {
+		if len(crdResults.Errs) > 0 {
 			failed = true
+			for _, err := range crdResults.Errs {
+				fmt.Fprintf(c.IOStreams.ErrOut, "Error during evalutions of crd/%v: %v\n", crdResults.Name(), err)
+			}
+		}
+		failed = c.writeTextResults(crdResults.Results) || failed
+	}
+	failed = c.writeTextResults(runResults) || failed
+
+	return allResults, failed, utilerrors.NewAggregate(append(errs, allErrs(allResults)...))
+}
+
+// writeTextResults writes the human readable messages of results and returns true when there were errors.
+func (c *CheckManifestConfig) writeTextResults(results []manifestcomparators.ComparisonResults) bool {
+	for _, comparisonResult := range results {
+		for _, msg := range comparisonResult.Errors {
 			fmt.Fprintf(c.IOStreams.ErrOut, "ERROR: %q: %v\n", comparisonResult.Name, msg)
 		}
 	}
-	for _, comparisonResult := range comparisonResults {
+	for _, comparisonResult := range results {
 		for _, msg := range comparisonResult.Warnings {
 			fmt.Fprintf(c.IOStreams.Out, "Warning: %q: %v\n", comparisonResult.Name, msg)
 		}
 	}
-	for _, comparisonResult := range comparisonResults {
+	for _, comparisonResult := range results {
 		for _, msg := range comparisonResult.Infos {
 			fmt.Fprintf(c.IOStreams.Out, "info: %q: %v\n", comparisonResult.Name, msg)
 		}
 	}
+	return hasErrors(results)
+}
+
+func hasErrors(results []manifestcomparators.ComparisonResults) bool {
+	for _, comparisonResult := range results {
+		if len(comparisonResult.Errors) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func allErrs(allResults []*CRDResults) []error {
+	ret := []error{}
+	for _, crdResults := range allResults {
+		ret = append(ret, crdResults.Errs...)
+	}
+	return ret
+}
 
-	return comparisonResults, failed, utilerrors.NewAggregate(errs)
+func (c *CheckManifestConfig) writeBaseline(allResults []*CRDResults) error {
+	crdNames := []string{}
+	comparisonResults := []manifestcomparators.ComparisonResults{}
+	for _, crdResults := range allResults {
+		crdNames = append(crdNames, crdResults.Name())
+		comparisonResults = append(comparisonResults, crdResults.Results...)
+	}
+	c.Baseline.Record(crdNames, comparisonResults)
+	return c.Baseline.WriteFile(c.WriteBaselineFile)
+}
+
+func (c *CheckManifestConfig) writeApprovalRequests(allResults []*CRDResults) error {
+	approvalRequests := &approvals.ApprovalList{Approvals: []approvals.Approval{}}
+	for _, crdResults := range allResults {
+		crdApprovalRequests, err := approvals.ApprovalRequests(crdResults.Results, crdResults.ExistingCRD, crdResults.NewCRD)
+		if err != nil {
+			return err
+		}
+		approvalRequests.Approvals = append(approvalRequests.Approvals, crdApprovalRequests.Approvals...)
+	}
+	if err := approvalRequests.WriteFile(c.ApprovalRequestsFile); err != nil {
+		return fmt.Errorf("cannot write approval requests: %w", err)
+	}
+	return nil
 }
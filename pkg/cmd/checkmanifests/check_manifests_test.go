@@ -0,0 +1,240 @@
+package checkmanifests
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/crd-schema-checker/pkg/cmd/options"
+	"github.com/openshift/crd-schema-checker/pkg/exceptions"
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
+	"github.com/openshift/crd-schema-checker/pkg/resourceread"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the expected output in testdata")
+
+func newTestConfig(t *testing.T, output string) (*CheckManifestConfig, *bytes.Buffer, *bytes.Buffer) {
+	existingManifests, err := resourceread.ReadCustomResourceDefinitionManifests(filepath.Join("testdata", "existing.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newManifests, err := resourceread.ReadCustomResourceDefinitionManifests(filepath.Join("testdata", "new.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	crdPairs, err := pairCRDs(existingManifests, newManifests)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := manifestcomparators.NewRegistry()
+	for _, comparator := range []manifestcomparators.CRDComparator{
+		manifestcomparators.NoBools(),
+		manifestcomparators.NoFieldRemoval(),
+		manifestcomparators.NoCRDRemoval(),
+	} {
+		if err := registry.AddComparator(comparator); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exceptionList, err := exceptions.ReadExceptionsFile(filepath.Join("testdata", "exceptions.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	streams, _, out, errOut := genericclioptions.NewTestIOStreams()
+	return &CheckManifestConfig{
+		CRDPairs: crdPairs,
+		ComparatorConfig: &options.ComparatorConfig{
+			ComparatorRegistry:    registry,
+			ComparatorNames:       registry.KnownComparators(),
+			Exceptions:            exceptionList,
+			ReportStaleExceptions: true,
+		},
+		Output:    output,
+		IOStreams: streams,
+	}, out, errOut
+}
+
+func TestRunOutput(t *testing.T) {
+	tests := []struct {
+		output       string
+		expectedFile string
+	}{
+		{output: OutputFormatText, expectedFile: "expected.txt"},
+		{output: OutputFormatJSON, expectedFile: "expected.json"},
+		{output: OutputFormatYAML, expectedFile: "expected.yaml"},
+		{output: OutputFormatSARIF, expectedFile: "expected.sarif"},
+		{output: OutputFormatJUnit, expectedFile: "expected.xml"},
+	}
+	for _, test := range tests {
+		t.Run(test.expectedFile, func(t *testing.T) {
+			config, out, errOut := newTestConfig(t, test.output)
+			_, failed, err := config.Run()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !failed {
+				t.Errorf("expected the new boolean and the removed CRD to fail the run")
+			}
+
+			// text output reports errors on stderr and everything else on stdout.
+			actual := append(errOut.Bytes(), out.Bytes()...)
+			expectedFile := filepath.Join("testdata", test.expectedFile)
+			if *updateGolden {
+				if err := os.WriteFile(expectedFile, actual, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := os.ReadFile(expectedFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(expected, actual) {
+				t.Errorf("unexpected output, rerun with -update to see the difference in git:\n%s", actual)
+			}
+		})
+	}
+}
+
+func TestRunRemovedCRD(t *testing.T) {
+	config, _, _ := newTestConfig(t, OutputFormatText)
+	config.ComparatorConfig.Exceptions = nil
+	allResults, _, _ := config.Run()
+
+	var removed *CRDResults
+	for _, crdResults := range allResults {
+		if crdResults.Name() == "gadgets.example.com" {
+			removed = crdResults
+		}
+	}
+	if removed == nil || removed.NewCRD != nil {
+		t.Fatalf("expected gadgets.example.com to be reported as removed")
+	}
+	if !removed.Failed() {
+		t.Errorf("expected the removed CRD to fail")
+	}
+	if len(removed.Results) != 1 || removed.Results[0].Name != "NoCRDRemoval" {
+		t.Fatalf("expected only NoCRDRemoval to run for the removed CRD: %#v", removed.Results)
+	}
+	if expected := "crd/gadgets.example.com may not be removed"; len(removed.Results[0].Errors) != 1 || removed.Results[0].Errors[0] != expected {
+		t.Errorf("expected %q, got %v", expected, removed.Results[0].Errors)
+	}
+
+	// without NoCRDRemoval a removed CRD is not checked at all.
+	config.ComparatorConfig.ComparatorNames = []string{"NoBools", "NoFieldRemoval"}
+	allResults, _, _ = config.Run()
+	for _, crdResults := range allResults {
+		if crdResults.Name() == "gadgets.example.com" && crdResults.Failed() {
+			t.Errorf("expected a disabled NoCRDRemoval to allow the removal: %#v", crdResults.Results)
+		}
+	}
+}
+
+func TestRunStaleExceptionsForMissingCRDs(t *testing.T) {
+	config, _, errOut := newTestConfig(t, OutputFormatText)
+	config.ComparatorConfig.FailOnStaleExceptions = true
+	_, failed, _ := config.Run()
+	if !failed {
+		t.Fatalf("expected the exception for a CRD that is not in any manifest to fail the run")
+	}
+	if expected := `ERROR: "StaleExceptions": exception for comparator/NoBools crd/doohickeys.example.com version/v1 field/^.spec.enabled from deads2k names a CRD that is not in any checked manifest and can be removed`; !bytes.Contains(errOut.Bytes(), []byte(expected)) {
+		t.Errorf("expected %q in\n%s", expected, errOut.Bytes())
+	}
+}
+
+func TestCompleteFindsMovedCRDsInGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Chdir(t.TempDir())
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	mustGit := func(args ...string) {
+		t.Helper()
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", strings.Join(args, " "), err, output)
+		}
+	}
+	mustWrite := func(filename, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mustGit("init", "-q")
+	mustWrite("manifests/widgets.yaml", crdManifest("widgets.example.com"))
+	mustWrite("other/gadgets.yaml", crdManifest("gadgets.example.com"))
+	mustGit("add", ".")
+	mustGit("-c", "commit.gpgsign=false", "commit", "-q", "-m", "initial")
+
+	// the directory is renamed, a CRD moves in from another directory, and one is created.
+	mustGit("mv", "manifests", "crds")
+	mustGit("mv", "other/gadgets.yaml", "crds/gadgets.yaml")
+	mustWrite("crds/sprockets.yaml", crdManifest("sprockets.example.com"))
+
+	o := NewCheckManifestOptions(genericclioptions.NewTestIOStreamsDiscard())
+	o.ExistingGitRef = "HEAD"
+	o.NewCRDFile = "crds"
+	config, err := o.Complete()
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := []string{}
+	for _, pair := range config.CRDPairs {
+		if pair.NewCRD == nil {
+			t.Errorf("expected crd/%v to be in the new manifests", pair.Name())
+		}
+		if pair.ExistingCRD == nil {
+			created = append(created, pair.Name())
+		}
+	}
+	if len(config.CRDPairs) != 3 || len(created) != 1 || created[0] != "sprockets.example.com" {
+		t.Errorf("expected only sprockets.example.com of 3 CRDs to be created, got %v of %d", created, len(config.CRDPairs))
+	}
+
+	// an explicitly named path that did not exist at the revision is not silently empty.
+	o.ExistingCRDFile = "crds"
+	if _, err := o.Complete(); !errors.Is(err, resourceread.ErrNotInRevision) {
+		t.Errorf("expected ErrNotInRevision for an explicit existing path, got %v", err)
+	}
+}
+
+func TestJUnitSuiteForCRDEvaluationErrors(t *testing.T) {
+	// a removed CRD only runs NoCRDRemoval, which has no result when it cannot be evaluated.
+	suite := junitSuiteForCRD([]string{"NoBools", "NoCRDRemoval"}, CRDReport{
+		ExistingCRD: "gadgets.example.com",
+		name:        "gadgets.example.com",
+		evaluationErrs: []error{
+			&manifestcomparators.ComparatorError{Comparator: "NoCRDRemoval", Err: errors.New("cannot evaluate")},
+			errors.New("belongs to no comparator"),
+		},
+	})
+
+	errored := []string{}
+	for _, testCase := range suite.TestCases {
+		if testCase.Error != nil {
+			errored = append(errored, testCase.Name)
+		}
+	}
+	if expected := []string{junitEvaluationErrorsTestCase, "NoCRDRemoval"}; strings.Join(errored, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected errored test cases %v, got %v", expected, errored)
+	}
+	if suite.Tests != len(suite.TestCases) || suite.Tests != 2 || suite.Errors != 2 || suite.Failures != 0 {
+		t.Errorf("expected 2 tests with 2 errors, got %d tests with %d errors and %d failures", suite.Tests, suite.Errors, suite.Failures)
+	}
+}
@@ -0,0 +1,159 @@
+package checkmanifests
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const OutputFormatJUnit = "junit"
+
+// junitEvaluationErrorsTestCase is the test case of the evaluation errors that belong to no comparator.
+const junitEvaluationErrorsTestCase = "EvaluationErrors"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnitReport writes one test suite per CRD with one test case per comparator that was requested and one per
+// additional result.
+func writeJUnitReport(out io.Writer, report *CheckManifestReport) error {
+	suites := junitTestSuites{
+		Name: "crd-schema-checker",
+	}
+
+	for _, crdReport := range report.CRDs {
+		suites.Suites = append(suites.Suites, junitSuiteForCRD(report.Comparators, crdReport))
+	}
+	if len(report.evaluationErrs) > 0 || len(report.Results) > 0 {
+		// results and errors that do not belong to a CRD still have to fail the run.
+		suites.Suites = append(suites.Suites, junitSuiteForCRD(nil, CRDReport{
+			Results:        report.Results,
+			name:           "crd-schema-checker",
+			evaluationErrs: report.evaluationErrs,
+		}))
+	}
+
+	for _, suite := range suites.Suites {
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+	}
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(out)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return fmt.Errorf("cannot encode report: %w", err)
+	}
+	if _, err := fmt.Fprintln(out); err != nil {
+		return err
+	}
+	return nil
+}
+
+func junitSuiteForCRD(comparators []string, crdReport CRDReport) junitTestSuite {
+	suite := junitTestSuite{
+		Name: crdReport.name,
+	}
+
+	comparatorErrors := map[string][]string{}
+	for _, err := range crdReport.evaluationErrs {
+		comparatorName := junitEvaluationErrorsTestCase
+		comparatorErr := &manifestcomparators.ComparatorError{}
+		if errors.As(err, &comparatorErr) {
+			comparatorName = comparatorErr.Comparator
+		}
+		comparatorErrors[comparatorName] = append(comparatorErrors[comparatorName], err.Error())
+	}
+
+	// results that are not produced by a comparator, like stale exceptions, get their own test case.  Removed CRDs are
+	// only checked by NoCRDRemoval, so only their results are test cases.  Every evaluation error is an errored test
+	// case, also when its comparator has no result, so the counts of the suite match its test cases.
+	testCaseNames := sets.NewString()
+	if len(crdReport.NewCRD) > 0 {
+		testCaseNames.Insert(comparators...)
+	}
+	for _, comparisonResult := range crdReport.Results {
+		testCaseNames.Insert(comparisonResult.Name)
+	}
+	testCaseNames.Insert(sets.StringKeySet(comparatorErrors).UnsortedList()...)
+
+	for _, comparatorName := range testCaseNames.List() {
+		testCase := junitTestCase{
+			Name:      comparatorName,
+			Classname: crdReport.name,
+		}
+
+		if errs := comparatorErrors[comparatorName]; len(errs) > 0 {
+			suite.Errors++
+			testCase.Error = &junitMessage{
+				Message: fmt.Sprintf("%d evaluation errors", len(errs)),
+				Type:    "EvaluationError",
+				Text:    strings.Join(errs, "\n"),
+			}
+		}
+
+		for _, comparisonResult := range crdReport.Results {
+			if comparisonResult.Name != comparatorName {
+				continue
+			}
+			if len(comparisonResult.Errors) > 0 {
+				suite.Failures++
+				testCase.Failure = &junitMessage{
+					Message: fmt.Sprintf("%d errors", len(comparisonResult.Errors)),
+					Type:    comparatorName,
+					Text:    strings.Join(comparisonResult.Errors, "\n"),
+				}
+			}
+
+			systemOut := []string{}
+			for _, msg := range comparisonResult.Warnings {
+				systemOut = append(systemOut, fmt.Sprintf("Warning: %v", msg))
+			}
+			for _, msg := range comparisonResult.Infos {
+				systemOut = append(systemOut, fmt.Sprintf("info: %v", msg))
+			}
+			testCase.SystemOut = strings.Join(systemOut, "\n")
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Tests = len(suite.TestCases)
+
+	return suite
+}
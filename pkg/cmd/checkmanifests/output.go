@@ -0,0 +1,131 @@
+package checkmanifests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
+	"github.com/openshift/crd-schema-checker/pkg/resourceread"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// OutputFormatText is the default, human readable output.
+	OutputFormatText = ""
+	OutputFormatJSON = "json"
+	OutputFormatYAML = "yaml"
+)
+
+// reportWriters holds the machine-readable output formats.  Text output is written directly by Run.
+var reportWriters = map[string]func(out io.Writer, report *CheckManifestReport) error{
+	OutputFormatJSON:  writeJSONReport,
+	OutputFormatYAML:  writeYAMLReport,
+	OutputFormatSARIF: writeSARIFReport,
+	OutputFormatJUnit: writeJUnitReport,
+}
+
+func knownOutputFormats() []string {
+	return []string{OutputFormatJSON, OutputFormatYAML, OutputFormatSARIF, OutputFormatJUnit}
+}
+
+// CheckManifestReport is the complete, machine-readable result of a check-manifests run.
+type CheckManifestReport struct {
+	// Comparators are the names of the comparators that were run.
+	Comparators []string `json:"comparators" yaml:"comparators"`
+
+	CRDs []CRDReport `json:"crds" yaml:"crds"`
+	// Results are the results that do not belong to a single CRD, like exceptions for CRDs that were not checked.
+	Results []manifestcomparators.ComparisonResults `json:"results" yaml:"results"`
+	// EvaluationErrors are the errors that do not belong to a single CRD.
+	EvaluationErrors []string `json:"evaluationErrors" yaml:"evaluationErrors"`
+
+	// Failed is true when any CRD failed or there were evaluation errors.
+	Failed bool `json:"failed" yaml:"failed"`
+
+	// evaluationErrs are the errors behind EvaluationErrors.
+	evaluationErrs []error
+}
+
+// CRDReport is the result of checking one CRD.
+type CRDReport struct {
+	// ExistingCRD is the name of the existing CRD.  Empty when the new CRD is a create.
+	ExistingCRD string `json:"existingCRD,omitempty" yaml:"existingCRD,omitempty"`
+	// NewCRD is the name of the new CRD.  Empty when the existing CRD was removed.
+	NewCRD string `json:"newCRD,omitempty" yaml:"newCRD,omitempty"`
+	// NewCRDFile is the manifest the new CRD was read from.
+	NewCRDFile string `json:"newCRDFilename,omitempty" yaml:"newCRDFilename,omitempty"`
+
+	Results          []manifestcomparators.ComparisonResults `json:"results" yaml:"results"`
+	EvaluationErrors []string                                `json:"evaluationErrors" yaml:"evaluationErrors"`
+
+	// Failed is true when any comparator reported an error or could not be evaluated.
+	Failed bool `json:"failed" yaml:"failed"`
+
+	// name is the name of the CRD, whether it was created, updated, or removed.
+	name string
+	// newCRDPositions locates findings in NewCRDFile.
+	newCRDPositions *resourceread.CustomResourceDefinitionPositions
+	// evaluationErrs are the errors behind EvaluationErrors, for formats that report them per comparator.
+	evaluationErrs []error
+}
+
+func (c *CheckManifestConfig) newReport(runResults []manifestcomparators.ComparisonResults, allResults []*CRDResults, errs []error, failed bool) *CheckManifestReport {
+	ret := &CheckManifestReport{
+		Comparators:      c.ComparatorConfig.ComparatorNames,
+		CRDs:             []CRDReport{},
+		Results:          []manifestcomparators.ComparisonResults{},
+		EvaluationErrors: []string{},
+		Failed:           failed,
+		evaluationErrs:   errs,
+	}
+	ret.Results = append(ret.Results, runResults...)
+	for _, err := range errs {
+		ret.EvaluationErrors = append(ret.EvaluationErrors, err.Error())
+	}
+
+	for _, crdResults := range allResults {
+		crdReport := CRDReport{
+			NewCRDFile:       crdResults.NewCRDFile,
+			Results:          crdResults.Results,
+			EvaluationErrors: []string{},
+			Failed:           crdResults.Failed(),
+			name:             crdResults.Name(),
+			newCRDPositions:  crdResults.NewCRDPositions,
+			evaluationErrs:   crdResults.Errs,
+		}
+		if crdResults.ExistingCRD != nil {
+			crdReport.ExistingCRD = crdResults.ExistingCRD.Name
+		}
+		if crdResults.NewCRD != nil {
+			crdReport.NewCRD = crdResults.NewCRD.Name
+		}
+		for _, err := range crdResults.Errs {
+			crdReport.EvaluationErrors = append(crdReport.EvaluationErrors, err.Error())
+		}
+		ret.CRDs = append(ret.CRDs, crdReport)
+	}
+
+	return ret
+}
+
+func writeJSONReport(out io.Writer, report *CheckManifestReport) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("cannot encode report: %w", err)
+	}
+	return nil
+}
+
+func writeYAMLReport(out io.Writer, report *CheckManifestReport) error {
+	reportBytes, err := yaml.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("cannot encode report: %w", err)
+	}
+	if _, err := out.Write(reportBytes); err != nil {
+		return err
+	}
+	return nil
+}
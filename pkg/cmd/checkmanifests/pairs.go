@@ -0,0 +1,67 @@
+package checkmanifests
+
+import (
+	"fmt"
+
+	"github.com/openshift/crd-schema-checker/pkg/resourceread"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// CRDPair is one CRD to check.  ExistingCRD is nil when the CRD is created and NewCRD is nil when it is removed.
+type CRDPair struct {
+	ExistingCRD *apiextensionsv1.CustomResourceDefinition
+	NewCRD      *apiextensionsv1.CustomResourceDefinition
+
+	// NewCRDFile and NewCRDPositions locate findings in the new CRD manifest.
+	NewCRDFile      string
+	NewCRDPositions *resourceread.CustomResourceDefinitionPositions
+}
+
+func (p CRDPair) Name() string {
+	if p.NewCRD != nil {
+		return p.NewCRD.Name
+	}
+	if p.ExistingCRD != nil {
+		return p.ExistingCRD.Name
+	}
+	return ""
+}
+
+// pairCRDs pairs the existing and new CRDs by metadata.name, in order of name.
+func pairCRDs(existingManifests, newManifests []resourceread.CustomResourceDefinitionManifest) ([]CRDPair, error) {
+	existingByName, err := manifestsByName(existingManifests)
+	if err != nil {
+		return nil, err
+	}
+	newByName, err := manifestsByName(newManifests)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []CRDPair{}
+	for _, name := range sets.StringKeySet(existingByName).Union(sets.StringKeySet(newByName)).List() {
+		pair := CRDPair{}
+		if existingManifest, ok := existingByName[name]; ok {
+			pair.ExistingCRD = existingManifest.CRD
+		}
+		if newManifest, ok := newByName[name]; ok {
+			pair.NewCRD = newManifest.CRD
+			pair.NewCRDFile = newManifest.Filename
+			pair.NewCRDPositions = newManifest.Positions
+		}
+		ret = append(ret, pair)
+	}
+	return ret, nil
+}
+
+func manifestsByName(manifests []resourceread.CustomResourceDefinitionManifest) (map[string]resourceread.CustomResourceDefinitionManifest, error) {
+	ret := map[string]resourceread.CustomResourceDefinitionManifest{}
+	for _, manifest := range manifests {
+		if other, ok := ret[manifest.CRD.Name]; ok {
+			return nil, fmt.Errorf("crd/%v is defined in both %v and %v", manifest.CRD.Name, other.Filename, manifest.Filename)
+		}
+		ret[manifest.CRD.Name] = manifest
+	}
+	return ret, nil
+}
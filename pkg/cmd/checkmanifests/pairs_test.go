@@ -0,0 +1,124 @@
+package checkmanifests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/openshift/crd-schema-checker/pkg/resourceread"
+)
+
+func crdManifest(name string) string {
+	return fmt.Sprintf(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: %v
+spec:
+  group: example.com
+  versions:
+    - name: v1
+      served: true
+      storage: true
+`, name)
+}
+
+func readTestManifests(t *testing.T, files map[string][]string) []resourceread.CustomResourceDefinitionManifest {
+	ret := []resourceread.CustomResourceDefinitionManifest{}
+	for filename, names := range files {
+		documents := []string{}
+		for _, name := range names {
+			documents = append(documents, crdManifest(name))
+		}
+		manifests, err := resourceread.ReadCustomResourceDefinitionManifestsFromBytes(filename, []byte(strings.Join(documents, "---\n")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret = append(ret, manifests...)
+	}
+	return ret
+}
+
+func TestPairCRDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing map[string][]string
+		new      map[string][]string
+		// expected are the pairs as existing>new, with - for a missing side.
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "one document",
+			existing: map[string][]string{"a.yaml": {"widgets.example.com"}},
+			new:      map[string][]string{"a.yaml": {"widgets.example.com"}},
+			expected: []string{"widgets.example.com>widgets.example.com"},
+		},
+		{
+			name:     "documents are paired by name, not by order",
+			existing: map[string][]string{"all.yaml": {"widgets.example.com", "gadgets.example.com"}},
+			new:      map[string][]string{"all.yaml": {"gadgets.example.com", "widgets.example.com"}},
+			expected: []string{"gadgets.example.com>gadgets.example.com", "widgets.example.com>widgets.example.com"},
+		},
+		{
+			name:     "moved to another file",
+			existing: map[string][]string{"all.yaml": {"widgets.example.com", "gadgets.example.com"}},
+			new:      map[string][]string{"widgets.yaml": {"widgets.example.com"}, "gadgets.yaml": {"gadgets.example.com"}},
+			expected: []string{"gadgets.example.com>gadgets.example.com", "widgets.example.com>widgets.example.com"},
+		},
+		{
+			name:     "created and removed",
+			existing: map[string][]string{"all.yaml": {"widgets.example.com", "gadgets.example.com"}},
+			new:      map[string][]string{"all.yaml": {"widgets.example.com", "sprockets.example.com"}},
+			expected: []string{"gadgets.example.com>-", "->sprockets.example.com", "widgets.example.com>widgets.example.com"},
+		},
+		{
+			name:     "no existing CRDs",
+			new:      map[string][]string{"all.yaml": {"widgets.example.com"}},
+			expected: []string{"->widgets.example.com"},
+		},
+		{
+			name:        "defined twice in one file",
+			new:         map[string][]string{"all.yaml": {"widgets.example.com", "widgets.example.com"}},
+			expectedErr: "crd/widgets.example.com is defined in both all.yaml and all.yaml",
+		},
+		{
+			name:        "defined twice in the existing CRDs",
+			existing:    map[string][]string{"all.yaml": {"widgets.example.com", "widgets.example.com"}},
+			new:         map[string][]string{"all.yaml": {"widgets.example.com"}},
+			expectedErr: "crd/widgets.example.com is defined in both all.yaml and all.yaml",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := pairCRDs(readTestManifests(t, test.existing), readTestManifests(t, test.new))
+			switch {
+			case len(test.expectedErr) > 0 && err == nil:
+				t.Fatalf("expected error %q", test.expectedErr)
+			case len(test.expectedErr) > 0 && err.Error() != test.expectedErr:
+				t.Fatalf("expected error %q, got %q", test.expectedErr, err)
+			case len(test.expectedErr) == 0 && err != nil:
+				t.Fatal(err)
+			case len(test.expectedErr) > 0:
+				return
+			}
+
+			actualPairs := []string{}
+			for _, pair := range actual {
+				existingName, newName := "-", "-"
+				if pair.ExistingCRD != nil {
+					existingName = pair.ExistingCRD.Name
+				}
+				if pair.NewCRD != nil {
+					newName = pair.NewCRD.Name
+					if pair.NewCRDPositions == nil || len(pair.NewCRDFile) == 0 {
+						t.Errorf("expected %v to be located in its manifest", newName)
+					}
+				}
+				actualPairs = append(actualPairs, existingName+">"+newName)
+			}
+			if strings.Join(actualPairs, ",") != strings.Join(test.expected, ",") {
+				t.Errorf("expected %v, got %v", test.expected, actualPairs)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package checkmanifests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
+)
+
+const (
+	OutputFormatSARIF = "sarif"
+
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// The types below are the subset of SARIF 2.1.0 needed to report findings as code-scanning annotations.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription sarifMessage  `json:"shortDescription"`
+	FullDescription  *sarifMessage `json:"fullDescription,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	RuleIndex  int               `json:"ruleIndex"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+func sarifLevel(severity manifestcomparators.Severity) string {
+	switch severity {
+	case manifestcomparators.SeverityError:
+		return "error"
+	case manifestcomparators.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func writeSARIFReport(out io.Writer, report *CheckManifestReport) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "crd-schema-checker",
+				InformationURI: "https://github.com/openshift/crd-schema-checker",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	// evaluation errors mean a comparator could not run, they are reported against the invocation, not a file.
+	invocation := sarifInvocation{ExecutionSuccessful: true}
+	addNotifications := func(evaluationErrors []string) {
+		for _, evaluationError := range evaluationErrors {
+			invocation.ExecutionSuccessful = false
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+				Level:   "error",
+				Message: sarifMessage{Text: evaluationError},
+			})
+		}
+	}
+	addNotifications(report.EvaluationErrors)
+
+	// every comparator is one rule, shared by the results of all CRDs.
+	ruleIndexes := map[string]int{}
+	addResults := func(results []manifestcomparators.ComparisonResults, crdReport *CRDReport) {
+		for _, comparisonResult := range results {
+			ruleIndex, ok := ruleIndexes[comparisonResult.Name]
+			if !ok {
+				ruleIndex = len(run.Tool.Driver.Rules)
+				ruleIndexes[comparisonResult.Name] = ruleIndex
+				rule := sarifRule{
+					ID:               comparisonResult.Name,
+					ShortDescription: sarifMessage{Text: comparisonResult.Name},
+				}
+				if len(comparisonResult.WhyItMatters) > 0 {
+					rule.FullDescription = &sarifMessage{Text: comparisonResult.WhyItMatters}
+				}
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+			}
+
+			for _, finding := range comparisonResult.Findings {
+				result := sarifResult{
+					RuleID:    comparisonResult.Name,
+					RuleIndex: ruleIndex,
+					Level:     sarifLevel(finding.Severity),
+					Message:   sarifMessage{Text: finding.Message},
+					Properties: map[string]string{
+						"crdName": finding.CRDName,
+						"version": finding.Version,
+						"field":   finding.Field,
+					},
+				}
+				if crdReport != nil && len(crdReport.NewCRDFile) > 0 {
+					position := crdReport.newCRDPositions.FieldPosition(finding.Version, finding.Field)
+					result.Locations = []sarifLocation{{
+						PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(crdReport.NewCRDFile)},
+							Region:           sarifRegion{StartLine: position.Line, StartColumn: position.Column},
+						},
+					}}
+				}
+				run.Results = append(run.Results, result)
+			}
+		}
+	}
+	for i := range report.CRDs {
+		addNotifications(report.CRDs[i].EvaluationErrors)
+		addResults(report.CRDs[i].Results, &report.CRDs[i])
+	}
+	// results that do not belong to a CRD have no manifest to point to.
+	addResults(report.Results, nil)
+	run.Invocations = []sarifInvocation{invocation}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}}); err != nil {
+		return fmt.Errorf("cannot encode report: %w", err)
+	}
+	return nil
+}
@@ -2,25 +2,43 @@ package options
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	"github.com/openshift/crd-schema-checker/pkg/approvals"
 	"github.com/openshift/crd-schema-checker/pkg/defaultcomparators"
+	"github.com/openshift/crd-schema-checker/pkg/exceptions"
 	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
 	"github.com/spf13/pflag"
 )
 
 type ComparatorOptions struct {
-	ComparatorRegistry        manifestcomparators.CRDComparatorRegistry
+	ComparatorRegistry manifestcomparators.CRDComparatorRegistry
+	// NewComparatorRegistry replaces the ComparatorRegistry when KubeVersions are set.
+	NewComparatorRegistry     func(manifestcomparators.KubeVersionRange) manifestcomparators.CRDComparatorRegistry
 	KnownComparators          []string
 	DefaultEnabledComparators []string
 	EnabledComparators        []string
 	DisabledComparators       []string
+
+	// WarningsAsErrors are comparator:group pairs whose warnings are reported as errors.
+	WarningsAsErrors []string
+
+	// KubeVersions are the Kubernetes versions, or oldest-newest ranges, that serve the CRDs.
+	KubeVersions []string
+
+	ExceptionsFile  string
+	ApprovalsFile   string
+	TrustedKeysFile string
 }
 
 func NewComparatorOptions() *ComparatorOptions {
 	o := &ComparatorOptions{
-		ComparatorRegistry: defaultcomparators.NewDefaultComparators(),
+		ComparatorRegistry:    defaultcomparators.NewDefaultComparators(),
+		NewComparatorRegistry: defaultcomparators.NewDefaultComparatorsForKubeVersions,
 	}
 	o.KnownComparators = o.ComparatorRegistry.KnownComparators()
 
@@ -33,6 +51,11 @@ func NewComparatorOptions() *ComparatorOptions {
 func (o *ComparatorOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringSliceVar(&o.DisabledComparators, "disabled-validators", o.DisabledComparators, "list of comparators that must be disabled")
 	fs.StringSliceVar(&o.EnabledComparators, "enabled-validators", o.EnabledComparators, "list of comparators that must be enabled")
+	fs.StringSliceVar(&o.WarningsAsErrors, "warnings-as-errors", o.WarningsAsErrors, "list of comparator:group pairs whose warnings are reported as errors for CRDs of that API group, for instance NoValidationLoosening:config.openshift.io. The group may be * for every group.")
+	fs.StringSliceVar(&o.KubeVersions, "kube-version", o.KubeVersions, "list of Kubernetes versions, as major.minor or a range of oldest-newest, that serve the CRDs. CEL rules must compile with the libraries of the oldest version and stay within the cost limits of every version. Defaults to the CEL compatibility version of the vendored Kubernetes libraries.")
+	fs.StringVar(&o.ExceptionsFile, "exceptions-file", o.ExceptionsFile, "file of allowed violations. Matching errors are reported as infos.")
+	fs.StringVar(&o.ApprovalsFile, "approvals-file", o.ApprovalsFile, "file of signed approvals of individual changes. Approved errors are reported as infos.")
+	fs.StringVar(&o.TrustedKeysFile, "trusted-keys-file", o.TrustedKeysFile, "file of ed25519 public keys, as authorized_keys lines or PEM, that may sign approvals.")
 }
 
 func (o *ComparatorOptions) Validate() error {
@@ -46,10 +69,40 @@ func (o *ComparatorOptions) Validate() error {
 	if diff := enabledComparators.Difference(knownComparators); len(diff) > 0 {
 		return fmt.Errorf("unknown comparators: %v", disabledComparators.List())
 	}
+	if _, err := parseWarningsAsErrors(o.WarningsAsErrors, knownComparators); err != nil {
+		return err
+	}
+	if len(o.KubeVersions) > 0 {
+		if _, err := manifestcomparators.ParseKubeVersionRange(o.KubeVersions); err != nil {
+			return fmt.Errorf("--kube-version: %w", err)
+		}
+	}
+	if len(o.ApprovalsFile) > 0 && len(o.TrustedKeysFile) == 0 {
+		return fmt.Errorf("--approvals-file requires --trusted-keys-file")
+	}
 
 	return nil
 }
 
+// parseWarningsAsErrors returns the API groups to escalate warnings for, by comparator name.
+func parseWarningsAsErrors(values []string, knownComparators sets.String) (map[string]sets.String, error) {
+	ret := map[string]sets.String{}
+	for _, value := range values {
+		comparatorName, group, ok := strings.Cut(value, ":")
+		if !ok || len(comparatorName) == 0 || len(group) == 0 {
+			return nil, fmt.Errorf("--warnings-as-errors must be comparator:group, got %q", value)
+		}
+		if !knownComparators.Has(comparatorName) {
+			return nil, fmt.Errorf("unknown comparators: %v", comparatorName)
+		}
+		if _, ok := ret[comparatorName]; !ok {
+			ret[comparatorName] = sets.NewString()
+		}
+		ret[comparatorName].Insert(group)
+	}
+	return ret, nil
+}
+
 // Complete fills in missing values before command execution.
 func (o *ComparatorOptions) Complete() (*ComparatorConfig, error) {
 	ret := &ComparatorConfig{
@@ -70,10 +123,164 @@ func (o *ComparatorOptions) Complete() (*ComparatorConfig, error) {
 	comparatorsToRun := sets.NewString(o.DefaultEnabledComparators...).Insert(o.EnabledComparators...).Delete(o.DisabledComparators...)
 	ret.ComparatorNames = comparatorsToRun.List()
 
+	warningsAsErrors, err := parseWarningsAsErrors(o.WarningsAsErrors, knownComparators)
+	if err != nil {
+		return nil, err
+	}
+	ret.WarningsAsErrors = warningsAsErrors
+
+	if len(o.KubeVersions) > 0 {
+		kubeVersions, err := manifestcomparators.ParseKubeVersionRange(o.KubeVersions)
+		if err != nil {
+			return nil, fmt.Errorf("--kube-version: %w", err)
+		}
+		ret.ComparatorRegistry = o.NewComparatorRegistry(kubeVersions)
+	}
+
+	if len(o.ExceptionsFile) > 0 {
+		exceptionList, err := exceptions.ReadExceptionsFile(o.ExceptionsFile)
+		if err != nil {
+			return nil, err
+		}
+		ret.Exceptions = exceptionList
+	}
+
+	if len(o.ApprovalsFile) > 0 {
+		approvalList, err := approvals.ReadApprovalsFile(o.ApprovalsFile)
+		if err != nil {
+			return nil, err
+		}
+		ret.Approvals = approvalList
+	}
+	if len(o.TrustedKeysFile) > 0 {
+		trustedKeys, err := approvals.ReadTrustedKeysFile(o.TrustedKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		ret.TrustedKeys = trustedKeys
+	}
+
 	return ret, nil
 }
 
 type ComparatorConfig struct {
 	ComparatorRegistry manifestcomparators.CRDComparatorRegistry
 	ComparatorNames    []string
+
+	// WarningsAsErrors holds, by comparator name, the API groups whose CRDs have that comparator's warnings reported as
+	// errors.  The group * matches every CRD.
+	WarningsAsErrors map[string]sets.String
+
+	// Exceptions are optional allowed violations applied to every comparison.
+	Exceptions *exceptions.ExceptionList
+	// ReportStaleExceptions adds the stale exceptions for the compared CRD as warnings, or as errors when
+	// FailOnStaleExceptions is set.
+	ReportStaleExceptions bool
+	FailOnStaleExceptions bool
+
+	// Approvals are optional signed approvals of individual changes.  Only signatures of TrustedKeys are accepted.
+	Approvals   *approvals.ApprovalList
+	TrustedKeys []approvals.TrustedKey
+}
+
+// Compare runs the configured comparators and applies the approvals and exceptions to the results.
+func (c *ComparatorConfig) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) ([]manifestcomparators.ComparisonResults, []error) {
+	now := time.Now()
+	comparatorNames := c.ComparatorNames
+	if newCRD == nil {
+		// a removed CRD has nothing to compare, only NoCRDRemoval accepts it.
+		removalName := manifestcomparators.NoCRDRemoval().Name()
+		comparatorNames = []string{}
+		_, err := c.ComparatorRegistry.GetComparator(removalName)
+		if err == nil && (len(c.ComparatorNames) == 0 || sets.NewString(c.ComparatorNames...).Has(removalName)) {
+			comparatorNames = []string{removalName}
+		}
+	}
+	comparisonResults, errs := []manifestcomparators.ComparisonResults{}, []error{}
+	if newCRD != nil || len(comparatorNames) > 0 {
+		comparisonResults, errs = c.ComparatorRegistry.Compare(existingCRD, newCRD, comparatorNames...)
+	}
+	comparisonResults = c.escalateWarnings(comparisonResults, existingCRD, newCRD)
+
+	comparisonResults, err := c.Approvals.Apply(comparisonResults, existingCRD, newCRD, c.TrustedKeys)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.Exceptions == nil {
+		return comparisonResults, errs
+	}
+
+	var staleExceptions []manifestcomparators.Finding
+	if c.ReportStaleExceptions {
+		staleExceptions = c.Exceptions.Stale(comparisonResults, crdName(existingCRD, newCRD), comparatorNames, now, c.staleExceptionSeverity())
+	}
+
+	comparisonResults = c.Exceptions.Apply(comparisonResults, now)
+	if c.ReportStaleExceptions {
+		comparisonResults = append(comparisonResults, manifestcomparators.NewComparisonResults(exceptions.StaleExceptionsName, exceptions.StaleExceptionsWhyItMatters, staleExceptions))
+	}
+	return comparisonResults, errs
+}
+
+// StaleExceptionsForMissingCRDs reports the exceptions for CRDs that are not one of crdNames, the CRDs of the whole run.
+// Compare cannot find them, because it only sees one CRD at a time.
+func (c *ComparatorConfig) StaleExceptionsForMissingCRDs(crdNames []string) []manifestcomparators.ComparisonResults {
+	if c.Exceptions == nil || !c.ReportStaleExceptions {
+		return nil
+	}
+	staleExceptions := c.Exceptions.StaleForMissingCRDs(crdNames, time.Now(), c.staleExceptionSeverity())
+	return []manifestcomparators.ComparisonResults{
+		manifestcomparators.NewComparisonResults(exceptions.StaleExceptionsName, exceptions.StaleExceptionsWhyItMatters, staleExceptions),
+	}
+}
+
+func (c *ComparatorConfig) staleExceptionSeverity() manifestcomparators.Severity {
+	if c.FailOnStaleExceptions {
+		return manifestcomparators.SeverityError
+	}
+	return manifestcomparators.SeverityWarning
+}
+
+// escalateWarnings reports the warnings of comparators configured in WarningsAsErrors as errors when the CRD belongs to
+// one of the configured groups.  This runs before approvals and exceptions so escalated errors can be approved.
+func (c *ComparatorConfig) escalateWarnings(results []manifestcomparators.ComparisonResults, existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) []manifestcomparators.ComparisonResults {
+	if len(c.WarningsAsErrors) == 0 {
+		return results
+	}
+	group := ""
+	switch {
+	case newCRD != nil:
+		group = newCRD.Spec.Group
+	case existingCRD != nil:
+		group = existingCRD.Spec.Group
+	}
+
+	ret := []manifestcomparators.ComparisonResults{}
+	for _, comparisonResult := range results {
+		groups := c.WarningsAsErrors[comparisonResult.Name]
+		if len(comparisonResult.Findings) == 0 || !(groups.Has(group) || groups.Has("*")) {
+			ret = append(ret, comparisonResult)
+			continue
+		}
+		findings := []manifestcomparators.Finding{}
+		for _, finding := range comparisonResult.Findings {
+			if finding.Severity == manifestcomparators.SeverityWarning {
+				finding.Severity = manifestcomparators.SeverityError
+			}
+			findings = append(findings, finding)
+		}
+		ret = append(ret, manifestcomparators.NewComparisonResults(comparisonResult.Name, comparisonResult.WhyItMatters, findings))
+	}
+	return ret
+}
+
+func crdName(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) string {
+	if newCRD != nil {
+		return newCRD.Name
+	}
+	if existingCRD != nil {
+		return existingCRD.Name
+	}
+	return ""
 }
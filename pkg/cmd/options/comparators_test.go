@@ -0,0 +1,144 @@
+package options
+
+import (
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/crd-schema-checker/pkg/exceptions"
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
+)
+
+func TestParseWarningsAsErrors(t *testing.T) {
+	knownComparators := sets.NewString("NoValidationLoosening")
+
+	actual, err := parseWarningsAsErrors([]string{"NoValidationLoosening:config.openshift.io", "NoValidationLoosening:*"}, knownComparators)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groups := actual["NoValidationLoosening"].List(); len(groups) != 2 || groups[0] != "*" || groups[1] != "config.openshift.io" {
+		t.Errorf("unexpected groups: %v", groups)
+	}
+
+	for _, invalid := range []string{"NoValidationLoosening", "NoValidationLoosening:", ":config.openshift.io", "Unknown:config.openshift.io"} {
+		if _, err := parseWarningsAsErrors([]string{invalid}, knownComparators); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestEscalateWarnings(t *testing.T) {
+	crd := func(group string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "schedulers." + group},
+			Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: group},
+		}
+	}
+	results := func(crdName string) []manifestcomparators.ComparisonResults {
+		return []manifestcomparators.ComparisonResults{
+			manifestcomparators.NewComparisonResults("NoValidationLoosening", "", []manifestcomparators.Finding{
+				manifestcomparators.NewWarning(crdName, "v1", "^.spec.name", "loosened"),
+				manifestcomparators.NewInfo(crdName, "v1", "^.spec.name", "info"),
+			}),
+			manifestcomparators.NewComparisonResults("Other", "", []manifestcomparators.Finding{
+				manifestcomparators.NewWarning(crdName, "v1", "^.spec.name", "other"),
+			}),
+		}
+	}
+	config := &ComparatorConfig{
+		WarningsAsErrors: map[string]sets.String{"NoValidationLoosening": sets.NewString("config.openshift.io")},
+	}
+
+	escalated := config.escalateWarnings(results("schedulers.config.openshift.io"), nil, crd("config.openshift.io"))
+	if len(escalated[0].Errors) != 1 || len(escalated[0].Warnings) != 0 || len(escalated[0].Infos) != 1 {
+		t.Errorf("expected the warning to be escalated: %#v", escalated[0])
+	}
+	if len(escalated[1].Errors) != 0 || len(escalated[1].Warnings) != 1 {
+		t.Errorf("expected other comparators to keep their warnings: %#v", escalated[1])
+	}
+
+	unchanged := config.escalateWarnings(results("schedulers.example.com"), nil, crd("example.com"))
+	if len(unchanged[0].Errors) != 0 || len(unchanged[0].Warnings) != 1 {
+		t.Errorf("expected other groups to keep their warnings: %#v", unchanged[0])
+	}
+}
+
+func TestCompleteKubeVersions(t *testing.T) {
+	o := NewComparatorOptions()
+	var actual manifestcomparators.KubeVersionRange
+	o.NewComparatorRegistry = func(kubeVersions manifestcomparators.KubeVersionRange) manifestcomparators.CRDComparatorRegistry {
+		actual = kubeVersions
+		return manifestcomparators.NewRegistry()
+	}
+
+	o.KubeVersions = []string{"1.28-1.30", "1.31"}
+	if err := o.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	config, err := o.Complete()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.String() != "1.28-1.31" {
+		t.Errorf("unexpected kube versions: %v", actual)
+	}
+	if config.ComparatorRegistry == o.ComparatorRegistry {
+		t.Errorf("expected the comparator registry to be replaced")
+	}
+
+	o.KubeVersions = []string{"1.24"}
+	if err := o.Validate(); err == nil {
+		t.Errorf("expected error for a version without CEL validation rules")
+	}
+}
+
+func TestCompareRemovedCRD(t *testing.T) {
+	existingCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "schedulers.config.openshift.io"},
+		Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: "config.openshift.io"},
+	}
+	registry := manifestcomparators.NewRegistry()
+	if err := registry.AddComparator(manifestcomparators.NoCRDRemoval()); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.AddComparator(manifestcomparators.NoBools()); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ComparatorConfig{ComparatorRegistry: registry}
+	results, errs := config.Compare(existingCRD, nil)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if len(results) != 1 || results[0].Name != "NoCRDRemoval" || len(results[0].Errors) != 1 {
+		t.Fatalf("expected only the NoCRDRemoval error: %#v", results)
+	}
+
+	config.ComparatorNames = []string{"NoBools"}
+	if results, _ := config.Compare(existingCRD, nil); len(results) != 0 {
+		t.Errorf("expected a disabled NoCRDRemoval to report nothing: %#v", results)
+	}
+
+	exceptionList, err := exceptions.ReadExceptions([]byte(`
+exceptions:
+- comparator: NoCRDRemoval
+  crd: schedulers.config.openshift.io
+  version: "*"
+  field: "*"
+  owner: deads2k
+  justification: replaced by schedulers.operator.openshift.io
+  link: https://github.com/openshift/api/pull/1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.ComparatorNames = []string{"NoCRDRemoval", "NoBools"}
+	config.Exceptions = exceptionList
+	config.ReportStaleExceptions = true
+	results, _ = config.Compare(existingCRD, nil)
+	if len(results) != 2 || len(results[0].Errors) != 0 || len(results[0].Infos) != 1 || len(results[1].Warnings) != 0 {
+		t.Errorf("expected the removal to be suppressed by a fresh exception: %#v", results)
+	}
+}
@@ -9,26 +9,45 @@ func must(err error) {
 }
 
 func NewDefaultComparators() manifestcomparators.CRDComparatorRegistry {
+	return NewDefaultComparatorsForKubeVersions(manifestcomparators.DefaultKubeVersionRange())
+}
+
+// NewDefaultComparatorsForKubeVersions returns the default comparators with CEL rules checked against the libraries of
+// kubeVersions.
+func NewDefaultComparatorsForKubeVersions(kubeVersions manifestcomparators.KubeVersionRange) manifestcomparators.CRDComparatorRegistry {
 	ret := manifestcomparators.NewRegistry()
 	must(ret.AddComparator(manifestcomparators.NoBools()))
 	must(ret.AddComparator(manifestcomparators.NoFloats()))
 	must(ret.AddComparator(manifestcomparators.NoUints()))
 	must(ret.AddComparator(manifestcomparators.NoFieldRemoval()))
+	must(ret.AddComparator(manifestcomparators.NoCRDRemoval()))
 	must(ret.AddComparator(manifestcomparators.NoEnumRemoval()))
+	must(ret.AddComparator(manifestcomparators.NoNewEnumValues()))
 	must(ret.AddComparator(manifestcomparators.NoMaps()))
 	must(ret.AddComparator(manifestcomparators.NoDataTypeChange()))
 	must(ret.AddComparator(manifestcomparators.MustHaveStatus()))
+	must(ret.AddComparator(manifestcomparators.NoUnsafeVersionChanges()))
+	must(ret.AddComparator(manifestcomparators.NoIdentityChange()))
+	must(ret.AddComparator(manifestcomparators.SchemasMustMatchWithoutConversion()))
 	must(ret.AddComparator(manifestcomparators.ListsMustHaveSSATags()))
 	must(ret.AddComparator(manifestcomparators.ConditionsMustHaveProperSSATags()))
+	must(ret.AddComparator(manifestcomparators.NoSSATopologyChange()))
+	must(ret.AddComparator(manifestcomparators.ListTypesMustBeValid()))
 	must(ret.AddComparator(manifestcomparators.NoNewRequiredFields()))
-	must(ret.AddComparator(manifestcomparators.MustNotExceedCostBudget()))
+	must(ret.AddComparator(manifestcomparators.MustNotExceedCostBudgetForKubeVersions(kubeVersions)))
+	must(ret.AddComparator(manifestcomparators.NoUnratchetedCELRulesForKubeVersions(kubeVersions)))
+	must(ret.AddComparator(manifestcomparators.NoSuspiciousCELRulesForKubeVersions(kubeVersions)))
+	must(ret.AddComparator(manifestcomparators.NoValidationTightening()))
+	must(ret.AddComparator(manifestcomparators.NoPatternOrFormatTightening()))
+	must(ret.AddComparator(manifestcomparators.NoValidationLoosening()))
+	must(ret.AddComparator(manifestcomparators.NoDefaultChange()))
+	must(ret.AddComparator(manifestcomparators.DefaultsMustBeValidForKubeVersions(kubeVersions)))
+	must(ret.AddComparator(manifestcomparators.NoPreserveUnknownFieldsOrNullableRemoval()))
 
 	/*
 		other useful comparators
 
 		2. don't change field types
-		3. don't tighten validation rules
-		4. don't loosen validation rules (warning)
 		6. conditions must match metav1.Conditions with proper SSA tags
 		7. all lists must have SSA tags
 		9. don't use floats
@@ -42,7 +61,6 @@ func NewDefaultComparators() manifestcomparators.CRDComparatorRegistry {
 		19. booleans cannot be defaulted
 		20. no uses of corev1.ObjectReference
 		21. no uses of corev1.LocalObjectReference
-		22. no new enumerated values (warning)
 		23. no removed enumerated values (error)
 		24. no replace in list (warning)
 
@@ -50,8 +68,6 @@ func NewDefaultComparators() manifestcomparators.CRDComparatorRegistry {
 		though it was a create and runs it a second time as though it was a "from" level that had dummy annotation change.
 
 		.annotations[version.path.to.field/compratorname] = github.com/openshift
-
-		Require cryptographically signed hash of offensive change to allow?
 	*/
 
 	return ret
@@ -0,0 +1,272 @@
+package exceptions
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
+	"gopkg.in/yaml.v2"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// DateFormat is the format of Exception.Expires.
+	DateFormat = "2006-01-02"
+
+	// StaleExceptionsName is the comparator name that stale exceptions are reported under.
+	StaleExceptionsName = "StaleExceptions"
+	// StaleExceptionsWhyItMatters describes why stale exceptions are reported.
+	StaleExceptionsWhyItMatters = "Exceptions that no longer match anything or have expired protect nothing.  Removing them keeps " +
+		"the list of allowed violations short enough that every remaining entry can be audited."
+)
+
+// ExceptionList is the content of an exceptions file.
+type ExceptionList struct {
+	Exceptions []Exception `yaml:"exceptions"`
+}
+
+// Exception allows errors of one comparator for matching fields.  Every exception must be traceable to the person who
+// allowed it and why.
+type Exception struct {
+	Comparator string `yaml:"comparator"`
+	CRD        string `yaml:"crd"`
+	// Version is a glob of version names like Field.  Use * for findings that are not version specific, like the
+	// removal of a CRD.
+	Version string `yaml:"version"`
+	// Field is a glob of simple locations, for instance ^.spec.legacy.*  The only wildcard is *, which matches any
+	// sequence of characters.  [*], the simple location of list items and map values, is matched literally.
+	Field string `yaml:"field"`
+
+	Owner         string `yaml:"owner"`
+	Justification string `yaml:"justification"`
+	Link          string `yaml:"link"`
+	// Expires is an optional date, formatted as DateFormat.  An exception no longer applies on or after this date.
+	Expires string `yaml:"expires,omitempty"`
+
+	expires *time.Time
+}
+
+func ReadExceptionsFile(filename string) (*ExceptionList, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read exceptions file: %w", err)
+	}
+	return ReadExceptions(content)
+}
+
+func ReadExceptions(content []byte) (*ExceptionList, error) {
+	ret := &ExceptionList{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, fmt.Errorf("cannot decode exceptions: %w", err)
+	}
+
+	errs := []error{}
+	for i := range ret.Exceptions {
+		if err := ret.Exceptions[i].complete(); err != nil {
+			errs = append(errs, fmt.Errorf("exceptions[%d]: %w", i, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+
+	return ret, nil
+}
+
+func (e *Exception) complete() error {
+	missing := []string{}
+	for _, required := range []struct{ name, value string }{
+		{"comparator", e.Comparator},
+		{"crd", e.CRD},
+		{"version", e.Version},
+		{"field", e.Field},
+		{"owner", e.Owner},
+		{"justification", e.Justification},
+		{"link", e.Link},
+	} {
+		if len(required.value) == 0 {
+			missing = append(missing, required.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required fields: %v", strings.Join(missing, ", "))
+	}
+
+	if len(e.Expires) > 0 {
+		expires, err := time.Parse(DateFormat, e.Expires)
+		if err != nil {
+			return fmt.Errorf("expires must be formatted as %v: %w", DateFormat, err)
+		}
+		e.expires = &expires
+	}
+
+	return nil
+}
+
+// Expired is true when the exception has an expiry date and now is on or after it.
+func (e *Exception) Expired(now time.Time) bool {
+	return e.expires != nil && !now.Before(*e.expires)
+}
+
+// Matches is true when the exception covers the finding, regardless of severity or expiry.
+func (e *Exception) Matches(finding manifestcomparators.Finding) bool {
+	return e.Comparator == finding.Comparator &&
+		e.CRD == finding.CRDName &&
+		globMatch(e.Version, finding.Version) &&
+		globMatch(e.Field, finding.Field)
+}
+
+// globMatch matches value against a pattern where * matches any sequence of characters and [*] matches itself.
+func globMatch(pattern, value string) bool {
+	// swap [*] for a character that cannot appear in a simple location so it isn't treated as a wildcard.
+	pattern = strings.ReplaceAll(pattern, "[*]", "\x00")
+	value = strings.ReplaceAll(value, "[*]", "\x00")
+
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(value, part)
+		if index < 0 {
+			return false
+		}
+		value = value[index+len(part):]
+	}
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
+
+// Apply turns every error covered by an unexpired exception into an info that names the exception.
+func (l *ExceptionList) Apply(results []manifestcomparators.ComparisonResults, now time.Time) []manifestcomparators.ComparisonResults {
+	if l == nil || len(l.Exceptions) == 0 {
+		return results
+	}
+
+	ret := []manifestcomparators.ComparisonResults{}
+	for _, comparisonResult := range results {
+		if len(comparisonResult.Findings) == 0 {
+			// results without findings only have rendered messages, which we cannot match.
+			ret = append(ret, comparisonResult)
+			continue
+		}
+		findings := []manifestcomparators.Finding{}
+		for _, finding := range comparisonResult.Findings {
+			if exception := l.exceptionFor(finding, now); exception != nil {
+				finding.Severity = manifestcomparators.SeverityInfo
+				finding.Message = fmt.Sprintf("suppressed by exception from %v (%v, %v): %v", exception.Owner, exception.Justification, exception.Link, finding.Message)
+			}
+			findings = append(findings, finding)
+		}
+		ret = append(ret, manifestcomparators.NewComparisonResults(comparisonResult.Name, comparisonResult.WhyItMatters, findings))
+	}
+
+	return ret
+}
+
+func (l *ExceptionList) exceptionFor(finding manifestcomparators.Finding, now time.Time) *Exception {
+	if finding.Severity != manifestcomparators.SeverityError {
+		return nil
+	}
+	for i := range l.Exceptions {
+		if l.Exceptions[i].Expired(now) {
+			continue
+		}
+		if l.Exceptions[i].Matches(finding) {
+			return &l.Exceptions[i]
+		}
+	}
+	return nil
+}
+
+// Stale returns a finding for every exception for crdName and one of comparatorNames that has expired or that matches
+// no finding in results.  results must be the results before Apply.  Exceptions for other CRDs or for comparators that
+// did not run are never stale here, because this comparison cannot tell whether they are still needed.  Exceptions for
+// CRDs that are not part of the run at all are reported by StaleForMissingCRDs.
+func (l *ExceptionList) Stale(results []manifestcomparators.ComparisonResults, crdName string, comparatorNames []string, now time.Time, severity manifestcomparators.Severity) []manifestcomparators.Finding {
+	if l == nil {
+		return nil
+	}
+
+	ranComparators := sets.NewString(comparatorNames...)
+	ret := []manifestcomparators.Finding{}
+	for i := range l.Exceptions {
+		exception := &l.Exceptions[i]
+		if exception.CRD != crdName || !ranComparators.Has(exception.Comparator) {
+			continue
+		}
+
+		var msg string
+		switch {
+		case exception.Expired(now):
+			msg = exception.expiredMessage()
+		case !exception.matchesAnyFinding(results):
+			msg = fmt.Sprintf("exception for comparator/%v crd/%v version/%v field/%v from %v matches no finding and can be removed", exception.Comparator, exception.CRD, exception.Version, exception.Field, exception.Owner)
+		default:
+			continue
+		}
+		ret = append(ret, exception.staleFinding(severity, msg))
+	}
+
+	return ret
+}
+
+// StaleForMissingCRDs returns a finding for every exception whose CRD is not one of crdNames, the CRDs of the whole
+// run.  Such an exception cannot match anything, whichever comparators ran.
+func (l *ExceptionList) StaleForMissingCRDs(crdNames []string, now time.Time, severity manifestcomparators.Severity) []manifestcomparators.Finding {
+	if l == nil {
+		return nil
+	}
+
+	checkedCRDs := sets.NewString(crdNames...)
+	ret := []manifestcomparators.Finding{}
+	for i := range l.Exceptions {
+		exception := &l.Exceptions[i]
+		if checkedCRDs.Has(exception.CRD) {
+			continue
+		}
+
+		msg := fmt.Sprintf("exception for comparator/%v crd/%v version/%v field/%v from %v names a CRD that is not in any checked manifest and can be removed", exception.Comparator, exception.CRD, exception.Version, exception.Field, exception.Owner)
+		if exception.Expired(now) {
+			msg = exception.expiredMessage()
+		}
+		ret = append(ret, exception.staleFinding(severity, msg))
+	}
+
+	return ret
+}
+
+func (e *Exception) expiredMessage() string {
+	return fmt.Sprintf("exception for comparator/%v crd/%v version/%v field/%v from %v expired on %v", e.Comparator, e.CRD, e.Version, e.Field, e.Owner, e.Expires)
+}
+
+func (e *Exception) staleFinding(severity manifestcomparators.Severity, msg string) manifestcomparators.Finding {
+	return manifestcomparators.Finding{
+		Severity: severity,
+		CRDName:  e.CRD,
+		Version:  e.Version,
+		Field:    e.Field,
+		Message:  msg,
+	}
+}
+
+// matchesAnyFinding is true when the exception matches a finding of any severity.  An exception that only matches
+// warnings or infos suppresses nothing today, but still documents an accepted violation that escalation could turn
+// into an error.
+func (e *Exception) matchesAnyFinding(results []manifestcomparators.ComparisonResults) bool {
+	for _, comparisonResult := range results {
+		for _, finding := range comparisonResult.Findings {
+			if e.Matches(finding) {
+				return true
+			}
+		}
+	}
+	return false
+}
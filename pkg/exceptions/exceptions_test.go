@@ -0,0 +1,252 @@
+package exceptions
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/crd-schema-checker/pkg/manifestcomparators"
+)
+
+const exceptionsFile = `
+exceptions:
+- comparator: NoBools
+  crd: schedulers.config.openshift.io
+  version: v1
+  field: ^.spec.legacy*
+  owner: deads2k
+  justification: shipped before the rule existed
+  link: https://github.com/openshift/api/pull/1
+- comparator: NoBools
+  crd: schedulers.config.openshift.io
+  version: v1
+  field: ^.spec.expired
+  owner: deads2k
+  justification: temporary
+  link: https://github.com/openshift/api/pull/2
+  expires: "2024-01-01"
+`
+
+func TestReadExceptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr string
+	}{
+		{
+			name:    "valid",
+			content: exceptionsFile,
+		},
+		{
+			name: "missing owner",
+			content: `
+exceptions:
+- comparator: NoBools
+  crd: schedulers.config.openshift.io
+  version: v1
+  field: ^.spec.foo
+  justification: because
+  link: https://github.com/openshift/api/pull/1
+`,
+			expectedErr: "exceptions[0]: missing required fields: owner",
+		},
+		{
+			name: "bad expiry",
+			content: `
+exceptions:
+- comparator: NoBools
+  crd: schedulers.config.openshift.io
+  version: v1
+  field: ^.spec.foo
+  owner: deads2k
+  justification: because
+  link: https://github.com/openshift/api/pull/1
+  expires: tomorrow
+`,
+			expectedErr: "exceptions[0]: expires must be formatted as 2006-01-02",
+		},
+		{
+			name: "unknown field",
+			content: `
+exceptions:
+- comparator: NoBools
+  owners: deads2k
+`,
+			expectedErr: "field owners not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadExceptions([]byte(test.content))
+			switch {
+			case len(test.expectedErr) == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case len(test.expectedErr) != 0 && err == nil:
+				t.Fatalf("expected error containing %q, got none", test.expectedErr)
+			case len(test.expectedErr) != 0 && !strings.Contains(err.Error(), test.expectedErr):
+				t.Fatalf("expected error containing %q, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	exceptionList, err := ReadExceptions([]byte(exceptionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const crdName = "schedulers.config.openshift.io"
+	results := []manifestcomparators.ComparisonResults{
+		manifestcomparators.NewComparisonResults("NoBools", "", []manifestcomparators.Finding{
+			manifestcomparators.NewError(crdName, "v1", "^.spec.legacyField", "legacy"),
+			manifestcomparators.NewError(crdName, "v1", "^.spec.legacyField.nested", "nested"),
+			manifestcomparators.NewError(crdName, "v1", "^.spec.newField", "new"),
+			manifestcomparators.NewError(crdName, "v2", "^.spec.legacyField", "other version"),
+			manifestcomparators.NewError(crdName, "v1", "^.spec.expired", "expired"),
+			manifestcomparators.NewWarning(crdName, "v1", "^.spec.legacyWarning", "warning"),
+		}),
+		manifestcomparators.NewComparisonResults("NoMaps", "", []manifestcomparators.Finding{
+			manifestcomparators.NewError(crdName, "v1", "^.spec.legacyMap", "other comparator"),
+		}),
+	}
+
+	actual := exceptionList.Apply(results, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	expectedErrors := []string{"new", "other version", "expired"}
+	if !reflect.DeepEqual(expectedErrors, actual[0].Errors) {
+		t.Errorf("expected errors %v, got %v", expectedErrors, actual[0].Errors)
+	}
+	expectedInfos := []string{
+		"suppressed by exception from deads2k (shipped before the rule existed, https://github.com/openshift/api/pull/1): legacy",
+		"suppressed by exception from deads2k (shipped before the rule existed, https://github.com/openshift/api/pull/1): nested",
+	}
+	if !reflect.DeepEqual(expectedInfos, actual[0].Infos) {
+		t.Errorf("expected infos %v, got %v", expectedInfos, actual[0].Infos)
+	}
+	if expectedWarnings := []string{"warning"}; !reflect.DeepEqual(expectedWarnings, actual[0].Warnings) {
+		t.Errorf("expected warnings %v, got %v", expectedWarnings, actual[0].Warnings)
+	}
+	if expectedErrors := []string{"other comparator"}; !reflect.DeepEqual(expectedErrors, actual[1].Errors) {
+		t.Errorf("expected errors %v, got %v", expectedErrors, actual[1].Errors)
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		expected       bool
+	}{
+		{pattern: "^.spec.foo", value: "^.spec.foo", expected: true},
+		{pattern: "^.spec.foo", value: "^.spec.foobar", expected: false},
+		{pattern: "^.spec.*", value: "^.spec.foo.bar", expected: true},
+		{pattern: "^.spec.list[*].name", value: "^.spec.list[*].name", expected: true},
+		{pattern: "^.spec.list[*].name", value: "^.spec.list[0].name", expected: false},
+		{pattern: "^.*.name", value: "^.spec.list[*].name", expected: true},
+		{pattern: "^.*.name", value: "^.name", expected: false},
+		{pattern: "*", value: "", expected: true},
+	}
+	for _, test := range tests {
+		if actual := globMatch(test.pattern, test.value); actual != test.expected {
+			t.Errorf("globMatch(%q, %q): expected %v, got %v", test.pattern, test.value, test.expected, actual)
+		}
+	}
+}
+
+func TestStale(t *testing.T) {
+	exceptionList, err := ReadExceptions([]byte(exceptionsFile + `
+- comparator: NoMaps
+  crd: schedulers.config.openshift.io
+  version: v1
+  field: ^.spec.unused
+  owner: deads2k
+  justification: not run
+  link: https://github.com/openshift/api/pull/3
+- comparator: NoBools
+  crd: other.config.openshift.io
+  version: v1
+  field: ^.spec.unused
+  owner: deads2k
+  justification: not compared
+  link: https://github.com/openshift/api/pull/4
+- comparator: NoBools
+  crd: schedulers.config.openshift.io
+  version: v1
+  field: ^.spec.unused
+  owner: deads2k
+  justification: no longer needed
+  link: https://github.com/openshift/api/pull/5
+- comparator: NoBools
+  crd: schedulers.config.openshift.io
+  version: v1
+  field: ^.spec.warned
+  owner: deads2k
+  justification: only matches a warning
+  link: https://github.com/openshift/api/pull/6
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const crdName = "schedulers.config.openshift.io"
+	results := []manifestcomparators.ComparisonResults{
+		manifestcomparators.NewComparisonResults("NoBools", "", []manifestcomparators.Finding{
+			manifestcomparators.NewError(crdName, "v1", "^.spec.legacyField", "legacy"),
+			manifestcomparators.NewError(crdName, "v1", "^.spec.expired", "expired"),
+			manifestcomparators.NewWarning(crdName, "v1", "^.spec.warned", "only a warning"),
+		}),
+	}
+
+	actual := exceptionList.Stale(results, crdName, []string{"NoBools"}, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), manifestcomparators.SeverityError)
+
+	expected := []manifestcomparators.Finding{
+		{
+			Severity: manifestcomparators.SeverityError,
+			CRDName:  crdName,
+			Version:  "v1",
+			Field:    "^.spec.expired",
+			Message:  "exception for comparator/NoBools crd/schedulers.config.openshift.io version/v1 field/^.spec.expired from deads2k expired on 2024-01-01",
+		},
+		{
+			Severity: manifestcomparators.SeverityError,
+			CRDName:  crdName,
+			Version:  "v1",
+			Field:    "^.spec.unused",
+			Message:  "exception for comparator/NoBools crd/schedulers.config.openshift.io version/v1 field/^.spec.unused from deads2k matches no finding and can be removed",
+		},
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected\n%v\n, got\n%v", expected, actual)
+	}
+}
+
+func TestStaleForMissingCRDs(t *testing.T) {
+	exceptionList, err := ReadExceptions([]byte(exceptionsFile + `
+- comparator: NoMaps
+  crd: removed.config.openshift.io
+  version: v1
+  field: ^.spec.unused
+  owner: deads2k
+  justification: not in the run
+  link: https://github.com/openshift/api/pull/3
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := exceptionList.StaleForMissingCRDs([]string{"schedulers.config.openshift.io"}, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), manifestcomparators.SeverityWarning)
+
+	expected := []manifestcomparators.Finding{
+		{
+			Severity: manifestcomparators.SeverityWarning,
+			CRDName:  "removed.config.openshift.io",
+			Version:  "v1",
+			Field:    "^.spec.unused",
+			Message:  "exception for comparator/NoMaps crd/removed.config.openshift.io version/v1 field/^.spec.unused from deads2k names a CRD that is not in any checked manifest and can be removed",
+		},
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected\n%v\n, got\n%v", expected, actual)
+	}
+}
@@ -0,0 +1,179 @@
+package manifestcomparators
+
+import (
+	"fmt"
+	"regexp"
+
+	celgo "github.com/google/cel-go/cel"
+	celast "github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsvalidation "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/validation"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/version"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	apiservercel "k8s.io/apiserver/pkg/cel"
+	"k8s.io/apiserver/pkg/cel/environment"
+)
+
+// compileXValidations compiles the x-kubernetes-validations of the last schema in schemas, which is the schema at
+// fldPath preceded by its ancestry, with the CEL libraries of the oldest version of kubeVersions.  The results are in
+// the order of the rules.  No results are returned when the schema has no rules.
+func compileXValidations(kubeVersions KubeVersionRange, schemas []*apiextensionsv1.JSONSchemaProps, fldPath *field.Path) ([]cel.CompilationResult, *apiextensionsvalidation.CELSchemaContext, error) {
+	celContext, err := extractCELContext(schemas, fldPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	typeInfo, err := celContext.TypeInfo()
+	if err != nil {
+		return nil, nil, err
+	}
+	if typeInfo == nil {
+		return nil, celContext, nil
+	}
+
+	compResults, err := cel.Compile(
+		typeInfo.Schema,
+		typeInfo.DeclType,
+		celconfig.PerCallLimit,
+		environment.MustBaseEnvSet(kubeVersions.Oldest),
+		cel.NewExpressionsEnvLoader(),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compile x-kubernetes-validations rules: %w", err)
+	}
+	return compResults, celContext, nil
+}
+
+// celSelfTypeName names the object type of self, so the types of the nested objects can be found by name.
+const celSelfTypeName = "selfType"
+
+// checkedXValidation holds the type-checked ASTs of a rule of x-kubernetes-validations.  rule is nil when the rule did
+// not compile, and messageExpression is nil when the rule has no messageExpression or it did not compile.
+type checkedXValidation struct {
+	rule              *celast.AST
+	messageExpression *celast.AST
+
+	// ruleUndefinedFields and messageExpressionUndefinedFields are the names of the fields that are selected but not
+	// declared in the schema, like a misspelled field, which keep the expressions from compiling.
+	ruleUndefinedFields              []string
+	messageExpressionUndefinedFields []string
+}
+
+// celSchemas resolves the expressions of type-checked rules to the structural schemas of the values they select.
+type celSchemas struct {
+	// self is the schema of self and oldSelf.
+	self *structuralschema.Structural
+	// objects are the schemas of the object types of the rules, by type name.
+	objects map[string]*structuralschema.Structural
+}
+
+// checkXValidations type checks the x-kubernetes-validations that compileXValidations compiles, with the same
+// declarations of self and oldSelf, so the types of every expression are known.  The results are in the order of the
+// rules.  Compilation errors are reported by MustNotExceedCostBudget, only the undefined fields are kept.
+func checkXValidations(kubeVersions KubeVersionRange, schemas []*apiextensionsv1.JSONSchemaProps, fldPath *field.Path) ([]*checkedXValidation, celSchemas, error) {
+	compResults, celContext, err := compileXValidations(kubeVersions, schemas, fldPath)
+	if err != nil || len(compResults) == 0 {
+		return nil, celSchemas{}, err
+	}
+	typeInfo, err := celContext.TypeInfo()
+	if err != nil {
+		return nil, celSchemas{}, err
+	}
+
+	scopedType := typeInfo.DeclType.MaybeAssignTypeName(celSelfTypeName)
+	objects := map[string]*structuralschema.Structural{}
+	indexCELObjectSchemas(scopedType, typeInfo.Schema, objects)
+
+	ret := make([]*checkedXValidation, len(compResults))
+	for i, rule := range typeInfo.Schema.XValidations {
+		env, err := newCELCheckEnv(kubeVersions.Oldest, scopedType, rule.OptionalOldSelf != nil && *rule.OptionalOldSelf)
+		if err != nil {
+			return nil, celSchemas{}, err
+		}
+		checked := &checkedXValidation{}
+		ruleAST, issues := env.Compile(rule.Rule)
+		if issues.Err() == nil && compResults[i].Program != nil {
+			checked.rule = ruleAST.NativeRep()
+		} else {
+			checked.ruleUndefinedFields = celUndefinedFields(issues)
+		}
+		if len(rule.MessageExpression) > 0 {
+			messageAST, issues := env.Compile(rule.MessageExpression)
+			if issues.Err() == nil && compResults[i].MessageExpression != nil {
+				checked.messageExpression = messageAST.NativeRep()
+			} else {
+				checked.messageExpressionUndefinedFields = celUndefinedFields(issues)
+			}
+		}
+		ret[i] = checked
+	}
+	return ret, celSchemas{self: typeInfo.Schema, objects: objects}, nil
+}
+
+// celUndefinedFieldPattern matches the error of the type checker for a field that the type of its object does not have.
+var celUndefinedFieldPattern = regexp.MustCompile(`^undefined field '(.*)'$`)
+
+// celUndefinedFields returns the names of the undefined fields in issues, in order.
+func celUndefinedFields(issues *celgo.Issues) []string {
+	ret := []string{}
+	for _, issue := range issues.Errors() {
+		if match := celUndefinedFieldPattern.FindStringSubmatch(issue.Message); match != nil {
+			ret = append(ret, match[1])
+		}
+	}
+	return ret
+}
+
+// newCELCheckEnv declares self and oldSelf the way cel.Compile does.
+func newCELCheckEnv(kubeVersion *version.Version, scopedType *apiservercel.DeclType, optionalOldSelf bool) (*celgo.Env, error) {
+	oldSelfType := scopedType.CelType()
+	if optionalOldSelf {
+		oldSelfType = types.NewOptionalType(oldSelfType)
+	}
+	envSet, err := environment.MustBaseEnvSet(kubeVersion).Extend(
+		environment.VersionedOptions{
+			IntroducedVersion: version.MajorMinor(1, 0),
+			EnvOptions:        []celgo.EnvOption{celgo.Variable(cel.ScopedVarName, scopedType.CelType())},
+			DeclTypes:         []*apiservercel.DeclType{scopedType},
+		},
+		environment.VersionedOptions{
+			IntroducedVersion: version.MajorMinor(1, 24),
+			EnvOptions:        []celgo.EnvOption{celgo.Variable(cel.OldScopedVarName, oldSelfType)},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return envSet.NewExpressionsEnv(), nil
+}
+
+// indexCELObjectSchemas walks the declared type and the structural schema together and records the schema of every
+// object type by its name.
+func indexCELObjectSchemas(declType *apiservercel.DeclType, s *structuralschema.Structural, objects map[string]*structuralschema.Structural) {
+	if declType == nil || s == nil {
+		return
+	}
+	switch {
+	case declType.IsObject():
+		objects[declType.TypeName()] = s
+		for name := range s.Properties {
+			escaped, ok := apiservercel.Escape(name)
+			if !ok {
+				continue
+			}
+			if declField, ok := declType.Fields[escaped]; ok {
+				property := s.Properties[name]
+				indexCELObjectSchemas(declField.Type, &property, objects)
+			}
+		}
+	case declType.IsList():
+		indexCELObjectSchemas(declType.ElemType, s.Items, objects)
+	case declType.IsMap():
+		if s.AdditionalProperties != nil {
+			indexCELObjectSchemas(declType.ElemType, s.AdditionalProperties.Structural, objects)
+		}
+	}
+}
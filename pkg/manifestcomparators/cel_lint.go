@@ -0,0 +1,323 @@
+package manifestcomparators
+
+import (
+	"fmt"
+	"math"
+
+	celast "github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/parser"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	"k8s.io/apimachinery/pkg/util/sets"
+	apiservercel "k8s.io/apiserver/pkg/cel"
+)
+
+// celLintIssue is a problem found in a single CEL expression.
+type celLintIssue struct {
+	// subject is the part of the expression the issue is about, like has(self.spec.name), to tell issues apart.
+	subject string
+	message string
+}
+
+// celFieldPath spells a field selection like self.spec.name, also when fields are selected with ?., so selections of
+// the same field can be matched.
+func celFieldPath(e celast.Expr, sourceInfo *celast.SourceInfo) string {
+	switch e.Kind() {
+	case celast.SelectKind:
+		return celFieldPath(e.AsSelect().Operand(), sourceInfo) + "." + e.AsSelect().FieldName()
+	case celast.CallKind:
+		if fieldName, ok := celOptionalSelectField(e); ok {
+			return celFieldPath(e.AsCall().Args()[0], sourceInfo) + "." + fieldName
+		}
+	}
+	text, err := parser.Unparse(e, sourceInfo)
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+// celOptionalSelectField returns the field of a selection with ?., which does not fail when the field is not set.
+func celOptionalSelectField(e celast.Expr) (string, bool) {
+	call := e.AsCall()
+	if call.FunctionName() != operators.OptSelect || len(call.Args()) != 2 || call.Args()[1].Kind() != celast.LiteralKind {
+		return "", false
+	}
+	fieldName, ok := call.Args()[1].AsLiteral().Value().(string)
+	return fieldName, ok
+}
+
+// field returns the schema of the object e selects from and the name and schema of the selected field, when e is a
+// selection of a field of an object whose schema is known.
+func (c celSchemas) field(checked *celast.AST, e celast.Expr) (*structuralschema.Structural, string, *structuralschema.Structural, bool) {
+	if e.Kind() != celast.SelectKind {
+		return nil, "", nil, false
+	}
+	operandType := checked.GetType(e.AsSelect().Operand().ID())
+	if operandType == nil || operandType.Kind() != types.StructKind {
+		return nil, "", nil, false
+	}
+	parent, ok := c.objects[operandType.TypeName()]
+	if !ok {
+		return nil, "", nil, false
+	}
+	for name, property := range parent.Properties {
+		if escaped, ok := apiservercel.Escape(name); ok && escaped == e.AsSelect().FieldName() {
+			return parent, name, &property, true
+		}
+	}
+	return nil, "", nil, false
+}
+
+// value returns the schema of the value of e, when e is self, oldSelf or a selection of a field with a known schema.
+func (c celSchemas) value(checked *celast.AST, e celast.Expr) (*structuralschema.Structural, bool) {
+	switch e.Kind() {
+	case celast.IdentKind:
+		if name := e.AsIdent(); name == cel.ScopedVarName || name == cel.OldScopedVarName {
+			return c.self, c.self != nil
+		}
+	case celast.SelectKind:
+		if e.AsSelect().IsTestOnly() {
+			return nil, false
+		}
+		_, _, property, ok := c.field(checked, e)
+		return property, ok
+	}
+	return nil, false
+}
+
+// lintCELExpression checks a type-checked rule or messageExpression.  Guards are only collected syntactically, a has()
+// anywhere in the expression, or in inheritedGuards, is considered to guard every access of the same field.  The
+// guards of the expression are returned, so the guards of a rule can be passed to its messageExpression.
+func lintCELExpression(checked *celast.AST, schemas celSchemas, inheritedGuards sets.String) ([]celLintIssue, sets.String) {
+	sourceInfo := checked.SourceInfo()
+	exprs := []celast.Expr{}
+	celast.PostOrderVisit(checked.Expr(), celast.NewExprVisitor(func(e celast.Expr) {
+		exprs = append(exprs, e)
+	}))
+
+	guarded := sets.NewString()
+	for _, e := range exprs {
+		switch e.Kind() {
+		case celast.SelectKind:
+			if e.AsSelect().IsTestOnly() {
+				guarded.Insert(celFieldPath(e, sourceInfo))
+			}
+		case celast.CallKind:
+			if _, ok := celOptionalSelectField(e); ok {
+				guarded.Insert(celFieldPath(e, sourceInfo))
+			}
+		}
+	}
+
+	allGuards := guarded.Union(inheritedGuards)
+	ret := []celLintIssue{}
+	reported := sets.NewString()
+	report := func(subject, message string) {
+		if reported.Has(message) {
+			return
+		}
+		reported.Insert(message)
+		ret = append(ret, celLintIssue{subject: subject, message: message})
+	}
+
+	for _, e := range exprs {
+		parent, name, property, ok := schemas.field(checked, e)
+		if !ok {
+			continue
+		}
+		path := celFieldPath(e, sourceInfo)
+		required := parent.ValueValidation != nil && sets.NewString(parent.ValueValidation.Required...).Has(name)
+		switch {
+		case e.AsSelect().IsTestOnly():
+			if required {
+				report(fmt.Sprintf("has(%v)", path), fmt.Sprintf("checks has(%v), which is always true because the field is required", path))
+			}
+		case required || property.Default.Object != nil || allGuards.Has(path):
+		default:
+			report(path, fmt.Sprintf("accesses %v without has(%v), which fails when the optional field is not set", path, path))
+		}
+	}
+
+	for _, e := range exprs {
+		if subject, message, ok := lintCELComparison(checked, schemas, e); ok {
+			report(subject, message)
+		}
+	}
+
+	return ret, guarded
+}
+
+var celComparisonOperators = map[string]string{
+	operators.Equals:        operators.Equals,
+	operators.NotEquals:     operators.NotEquals,
+	operators.Less:          operators.Greater,
+	operators.LessEquals:    operators.GreaterEquals,
+	operators.Greater:       operators.Less,
+	operators.GreaterEquals: operators.LessEquals,
+}
+
+// lintCELComparison reports comparisons that always have the same result: comparisons of identical expressions,
+// of constants, and of values whose range is bounded by the schema with constants outside that range.
+func lintCELComparison(checked *celast.AST, schemas celSchemas, e celast.Expr) (string, string, bool) {
+	if e.Kind() != celast.CallKind {
+		return "", "", false
+	}
+	call := e.AsCall()
+	mirrored, ok := celComparisonOperators[call.FunctionName()]
+	if !ok || len(call.Args()) != 2 {
+		return "", "", false
+	}
+	lhs, rhs := call.Args()[0], call.Args()[1]
+	sourceInfo := checked.SourceInfo()
+	text, err := parser.Unparse(e, sourceInfo)
+	if err != nil {
+		return "", "", false
+	}
+	lhsText, lhsErr := parser.Unparse(lhs, sourceInfo)
+	rhsText, rhsErr := parser.Unparse(rhs, sourceInfo)
+
+	var result, known bool
+	switch {
+	case lhsErr == nil && rhsErr == nil && lhsText == rhsText:
+		result = call.FunctionName() == operators.Equals || call.FunctionName() == operators.LessEquals || call.FunctionName() == operators.GreaterEquals
+		known = true
+	case lhs.Kind() == celast.LiteralKind && rhs.Kind() == celast.LiteralKind:
+		result, known = compareCELLiterals(call.FunctionName(), lhs.AsLiteral(), rhs.AsLiteral())
+	case rhs.Kind() == celast.LiteralKind:
+		result, known = compareCELRange(checked, schemas, call.FunctionName(), lhs, rhs.AsLiteral())
+	case lhs.Kind() == celast.LiteralKind:
+		result, known = compareCELRange(checked, schemas, mirrored, rhs, lhs.AsLiteral())
+	}
+	if !known {
+		return "", "", false
+	}
+	return text, fmt.Sprintf("compares %v, which is always %v", text, result), true
+}
+
+func compareCELLiterals(operator string, lhs, rhs ref.Val) (bool, bool) {
+	switch operator {
+	case operators.Equals:
+		return lhs.Equal(rhs) == types.True, true
+	case operators.NotEquals:
+		return lhs.Equal(rhs) != types.True, true
+	}
+	comparer, ok := lhs.(traits.Comparer)
+	if !ok {
+		return false, false
+	}
+	cmp, ok := comparer.Compare(rhs).(types.Int)
+	if !ok {
+		return false, false
+	}
+	switch operator {
+	case operators.Less:
+		return cmp < 0, true
+	case operators.LessEquals:
+		return cmp <= 0, true
+	case operators.Greater:
+		return cmp > 0, true
+	default:
+		return cmp >= 0, true
+	}
+}
+
+func celNumber(value ref.Val) (float64, bool) {
+	switch v := value.Value().(type) {
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// celRange returns the bounds the schema puts on the value of e, for fields and their size().
+func celRange(checked *celast.AST, schemas celSchemas, e celast.Expr) (float64, float64, bool) {
+	boundOr := func(value float64, set bool, unset float64) float64 {
+		if !set {
+			return unset
+		}
+		return value
+	}
+
+	if e.Kind() == celast.CallKind && e.AsCall().FunctionName() == "size" {
+		call := e.AsCall()
+		var target celast.Expr
+		switch {
+		case call.IsMemberFunction() && len(call.Args()) == 0:
+			target = call.Target()
+		case !call.IsMemberFunction() && len(call.Args()) == 1:
+			target = call.Args()[0]
+		default:
+			return 0, 0, false
+		}
+		field, ok := schemas.value(checked, target)
+		if !ok {
+			return 0, 0, false
+		}
+		validation := field.ValueValidation
+		if validation == nil {
+			validation = &structuralschema.ValueValidation{}
+		}
+		switch field.Type {
+		case "string":
+			lo, loSet := intBound(validation.MinLength)
+			hi, hiSet := intBound(validation.MaxLength)
+			return boundOr(lo, loSet, 0), boundOr(hi, hiSet, math.Inf(1)), true
+		case "array":
+			lo, loSet := intBound(validation.MinItems)
+			hi, hiSet := intBound(validation.MaxItems)
+			return boundOr(lo, loSet, 0), boundOr(hi, hiSet, math.Inf(1)), true
+		case "object":
+			lo, loSet := intBound(validation.MinProperties)
+			hi, hiSet := intBound(validation.MaxProperties)
+			return boundOr(lo, loSet, 0), boundOr(hi, hiSet, math.Inf(1)), true
+		}
+		return 0, 0, false
+	}
+
+	field, ok := schemas.value(checked, e)
+	if !ok || (field.Type != "integer" && field.Type != "number") {
+		return 0, 0, false
+	}
+	if field.ValueValidation == nil {
+		return math.Inf(-1), math.Inf(1), true
+	}
+	lo, loSet := floatBound(field.ValueValidation.Minimum)
+	hi, hiSet := floatBound(field.ValueValidation.Maximum)
+	// exclusive bounds are ignored, which only misses some comparisons.
+	return boundOr(lo, loSet && !field.ValueValidation.ExclusiveMinimum, math.Inf(-1)), boundOr(hi, hiSet && !field.ValueValidation.ExclusiveMaximum, math.Inf(1)), true
+}
+
+func compareCELRange(checked *celast.AST, schemas celSchemas, operator string, e celast.Expr, literal ref.Val) (bool, bool) {
+	n, ok := celNumber(literal)
+	if !ok {
+		return false, false
+	}
+	lo, hi, ok := celRange(checked, schemas, e)
+	if !ok {
+		return false, false
+	}
+	switch operator {
+	case operators.Less:
+		return hi < n, hi < n || lo >= n
+	case operators.LessEquals:
+		return hi <= n, hi <= n || lo > n
+	case operators.Greater:
+		return lo > n, lo > n || hi <= n
+	case operators.GreaterEquals:
+		return lo >= n, lo >= n || hi < n
+	case operators.Equals:
+		return lo == n && hi == n, n < lo || n > hi || (lo == n && hi == n)
+	case operators.NotEquals:
+		return !(lo == n && hi == n), n < lo || n > hi || (lo == n && hi == n)
+	}
+	return false, false
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -27,12 +28,12 @@ func (conditionsMustHaveProperSSATags) WhyItMatters() string {
 }
 
 func (c conditionsMustHaveProperSSATags) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
-	errsToReport := []string{}
+	errsToReport := []Finding{}
 
 	for _, newVersion := range crd.Spec.Versions {
 		conditionsWithoutMapListType := []string{}
 		conditionsWithoutListMapKeysType := []string{}
-		invalidConditionsProperties := []string{}
+		invalidConditionsProperties := map[string][]string{}
 		SchemaHas(newVersion.Schema.OpenAPIV3Schema, field.NewPath("^"), field.NewPath("^"), nil,
 			func(s *apiextensionsv1.JSONSchemaProps, fldPath, simpleLocation *field.Path, _ []*apiextensionsv1.JSONSchemaProps) bool {
 				if s.Type != "array" {
@@ -45,7 +46,7 @@ func (c conditionsMustHaveProperSSATags) Validate(crd *apiextensionsv1.CustomRes
 
 				errs := validateConditionProperties(s.Items.Schema.Properties)
 				if len(errs) != 0 {
-					invalidConditionsProperties = append(invalidConditionsProperties, errs...)
+					invalidConditionsProperties[simpleLocation.String()] = append(invalidConditionsProperties[simpleLocation.String()], errs...)
 				}
 
 				if s.XListType == nil || *s.XListType != "map" {
@@ -59,29 +60,24 @@ func (c conditionsMustHaveProperSSATags) Validate(crd *apiextensionsv1.CustomRes
 				return false
 			})
 
-		for _, invalidConditionProp := range invalidConditionsProperties {
-			errStr := fmt.Sprintf("crd/%v version/%v field/^.status.condition must define valid condition properties: %s", crd.Name, newVersion.Name, invalidConditionProp)
-			errsToReport = append(errsToReport, errStr)
+		for _, affectedField := range sets.StringKeySet(invalidConditionsProperties).List() {
+			for _, invalidConditionProp := range invalidConditionsProperties[affectedField] {
+				errStr := fmt.Sprintf("crd/%v version/%v field/^.status.condition must define valid condition properties: %s", crd.Name, newVersion.Name, invalidConditionProp)
+				errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, affectedField, errStr).WithDiscriminator(invalidConditionProp))
+			}
 		}
 
 		for _, affectedField := range conditionsWithoutMapListType {
 			errStr := fmt.Sprintf("crd/%v version/%v field/%v must set x-kubernetes-list-type with value \"map\"", crd.Name, newVersion.Name, affectedField)
-			errsToReport = append(errsToReport, errStr)
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, affectedField, errStr).WithDiscriminator("x-kubernetes-list-type"))
 		}
 		for _, affectedField := range conditionsWithoutListMapKeysType {
 			errStr := fmt.Sprintf("crd/%v version/%v field/%v must set x-kubernetes-list-map-keys with single \"type\" value", crd.Name, newVersion.Name, affectedField)
-			errsToReport = append(errsToReport, errStr)
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, affectedField, errStr).WithDiscriminator("x-kubernetes-list-map-keys"))
 		}
 	}
 
-	return ComparisonResults{
-		Name:         c.Name(),
-		WhyItMatters: c.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: nil,
-		Infos:    nil,
-	}, nil
+	return NewComparisonResults(c.Name(), c.WhyItMatters(), errsToReport), nil
 }
 
 func (b conditionsMustHaveProperSSATags) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
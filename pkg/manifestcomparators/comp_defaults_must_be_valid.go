@@ -0,0 +1,186 @@
+package manifestcomparators
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel/model"
+	structuraldefaulting "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"k8s.io/apiserver/pkg/cel/environment"
+)
+
+type defaultsMustBeValid struct {
+	kubeVersions KubeVersionRange
+}
+
+func DefaultsMustBeValid() CRDComparator {
+	return DefaultsMustBeValidForKubeVersions(DefaultKubeVersionRange())
+}
+
+// DefaultsMustBeValidForKubeVersions checks defaults against the x-kubernetes-validations that compile with the CEL
+// libraries of the oldest version of kubeVersions.
+func DefaultsMustBeValidForKubeVersions(kubeVersions KubeVersionRange) CRDComparator {
+	return defaultsMustBeValid{kubeVersions: kubeVersions}
+}
+
+func (defaultsMustBeValid) Name() string {
+	return "DefaultsMustBeValid"
+}
+
+func (defaultsMustBeValid) WhyItMatters() string {
+	return "A default that does not satisfy the pattern, enum, bounds, or x-kubernetes-validations of its own field is " +
+		"only rejected when the apiserver applies it, so users who never set the field get errors about a value they " +
+		"did not write."
+}
+
+func (b defaultsMustBeValid) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	errsToReport := []Finding{}
+
+	for _, newVersion := range crd.Spec.Versions {
+		if newVersion.Schema == nil || newVersion.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		schema := &apiextensions.JSONSchemaProps{}
+		if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(newVersion.Schema.OpenAPIV3Schema, schema, nil); err != nil {
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, "", err.Error()))
+			continue
+		}
+		structural, err := structuralschema.NewStructural(schema)
+		if err != nil {
+			// the apiserver rejects schemas that are not structural before it looks at their defaults.
+			continue
+		}
+		// the oldest apiserver rejects rules that do not compile before it looks at the defaults, and
+		// MustNotExceedCostBudget reports them.
+		dropUncompiledRules(structural, true, environment.MustBaseEnvSet(b.kubeVersions.Oldest))
+
+		// these are the same checks the apiserver runs on defaults when the CRD is written, v1 CRDs always prune.
+		validationErrs, err := structuraldefaulting.ValidateDefaults(context.TODO(), field.NewPath("^"), structural, true, true)
+		if err == nil {
+			var mapValueErrs field.ErrorList
+			mapValueErrs, err = validateMapValueDefaults(field.NewPath("^"), structural)
+			validationErrs = append(validationErrs, mapValueErrs...)
+		}
+		if err != nil {
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, "", fmt.Sprintf("crd/%v version/%v defaults cannot be validated: %v", crd.Name, newVersion.Name, err)))
+			continue
+		}
+
+		schemas := getSchemasByPath(&newVersion)
+		for _, validationErr := range validationErrs {
+			simpleLocation, valuePath := locateDefaultError(schemas, validationErr.Field)
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation,
+				fmt.Sprintf("crd/%v version/%v field/%v %v does not satisfy its schema: %v", crd.Name, newVersion.Name, simpleLocation, valuePath, validationErr.ErrorBody())).WithDiscriminator(fmt.Sprintf("%v %v", valuePath, validationErr.ErrorBody())))
+		}
+	}
+
+	// errors of sibling properties are in map order.
+	sort.SliceStable(errsToReport, func(i, j int) bool {
+		return errsToReport[i].Message < errsToReport[j].Message
+	})
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
+}
+
+func (b defaultsMustBeValid) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	return RatchetCompare(b, existingCRD, newCRD)
+}
+
+// dropUncompiledRules removes the x-kubernetes-validations that do not compile with envSet from s and the schemas
+// below it.
+func dropUncompiledRules(s *structuralschema.Structural, isResourceRoot bool, envSet *environment.EnvSet) {
+	if s == nil {
+		return
+	}
+	if len(s.XValidations) > 0 {
+		compResults, err := cel.Compile(s, model.SchemaDeclType(s, isResourceRoot), celconfig.PerCallLimit, envSet, cel.NewExpressionsEnvLoader())
+		if err == nil {
+			compiledRules := apiextensionsv1.ValidationRules{}
+			for i, compResult := range compResults {
+				if compResult.Error == nil {
+					compiledRules = append(compiledRules, s.XValidations[i])
+				}
+			}
+			s.XValidations = compiledRules
+		}
+	}
+
+	for name, property := range s.Properties {
+		dropUncompiledRules(&property, property.XEmbeddedResource, envSet)
+		s.Properties[name] = property
+	}
+	if s.Items != nil {
+		dropUncompiledRules(s.Items, s.Items.XEmbeddedResource, envSet)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Structural != nil {
+		dropUncompiledRules(s.AdditionalProperties.Structural, s.AdditionalProperties.Structural.XEmbeddedResource, envSet)
+	}
+}
+
+// validateMapValueDefaults checks the defaults inside additionalProperties, which ValidateDefaults does not follow, but
+// which the apiserver applies to the values of maps.
+func validateMapValueDefaults(fldPath *field.Path, s *structuralschema.Structural) (field.ErrorList, error) {
+	if s == nil {
+		return nil, nil
+	}
+	allErrs := field.ErrorList{}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Structural != nil {
+		mapValuePath := fldPath.Child("additionalProperties")
+		errs, err := structuraldefaulting.ValidateDefaults(context.TODO(), mapValuePath, s.AdditionalProperties.Structural, false, true)
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, errs...)
+		errs, err = validateMapValueDefaults(mapValuePath, s.AdditionalProperties.Structural)
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+	if s.Items != nil {
+		errs, err := validateMapValueDefaults(fldPath.Child("items"), s.Items)
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+	for name, property := range s.Properties {
+		errs, err := validateMapValueDefaults(fldPath.Child("properties").Key(name), &property)
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+	return allErrs, nil
+}
+
+// locateDefaultError returns the simple location of the schema whose default is invalid and the path of the invalid
+// value inside that default, like default.spec.mode.  The errors of ValidateDefaults are located at the schema path of
+// the default followed by the path inside the default value.  Their schema paths name the schema of map values
+// additionalProperties, where the paths of getSchemasByPath name it additionalProperties.schema.
+func locateDefaultError(schemas map[string]locatedSchema, errPath string) (string, string) {
+	schemaPath, valuePath := errPath, ""
+	if i := strings.Index(errPath, ".default"); i >= 0 {
+		schemaPath, valuePath = errPath[:i], errPath[i:]
+	}
+	schemaPath = strings.TrimSuffix(strings.ReplaceAll(schemaPath+".", ".additionalProperties.", ".additionalProperties.schema."), ".")
+	errPath = schemaPath + valuePath
+
+	longestPath := ""
+	for path := range schemas {
+		if len(path) > len(longestPath) && strings.HasPrefix(errPath, path+".default") {
+			longestPath = path
+		}
+	}
+	if len(longestPath) == 0 {
+		return errPath, "default"
+	}
+	return schemas[longestPath].simpleLocation, strings.TrimPrefix(errPath, longestPath+".")
+}
@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestDefaultsMustBeValid(t *testing.T) {
+	RunAllTestsInDirForComparator(t, DefaultsMustBeValid(), "testdata/defaults_must_be_valid")
+}
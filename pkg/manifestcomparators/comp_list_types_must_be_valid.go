@@ -0,0 +1,135 @@
+package manifestcomparators
+
+import (
+	"fmt"
+	"sort"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type listTypesMustBeValid struct{}
+
+func ListTypesMustBeValid() CRDComparator {
+	return listTypesMustBeValid{}
+}
+
+func (listTypesMustBeValid) Name() string {
+	return "ListTypesMustBeValid"
+}
+
+func (listTypesMustBeValid) WhyItMatters() string {
+	return "Server-side apply merges lists by their x-kubernetes-list-type.  A map list is keyed by its " +
+		"x-kubernetes-list-map-keys, so every key must be a required or defaulted scalar property of object items, and a " +
+		"set list compares whole items, so its items must be scalars or atomic.  The apiserver rejects CRDs that break " +
+		"these rules, and items that cannot be keyed or compared merge badly."
+}
+
+func (b listTypesMustBeValid) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	errsToReport := []Finding{}
+
+	for _, newVersion := range crd.Spec.Versions {
+		schemas := getSchemasByPath(&newVersion)
+		paths := []string{}
+		for path := range schemas {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			s := schemas[path].schema
+			simpleLocation := schemas[path].simpleLocation
+			report := func(location, discriminator, msg string) {
+				errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, location, fmt.Sprintf("crd/%v version/%v field/%v %v", crd.Name, newVersion.Name, location, msg)).WithDiscriminator(discriminator))
+			}
+			listType := stringOrEmpty(s.XListType)
+
+			if s.XListType != nil && s.Type != "array" {
+				report(simpleLocation, "x-kubernetes-list-type", fmt.Sprintf("sets x-kubernetes-list-type=%v but is of type %q, only arrays may set it", listType, s.Type))
+				continue
+			}
+			if len(s.XListMapKeys) > 0 && listType != "map" {
+				report(simpleLocation, "x-kubernetes-list-map-keys", fmt.Sprintf("sets x-kubernetes-list-map-keys but x-kubernetes-list-type is %q, it must be map", listType))
+			}
+
+			var items *apiextensionsv1.JSONSchemaProps
+			if s.Items != nil {
+				items = s.Items.Schema
+			}
+			itemsLocation := simpleLocation + "[*]"
+
+			switch listType {
+			case "", "atomic":
+			case "map":
+				if len(s.XListMapKeys) == 0 {
+					report(simpleLocation, "x-kubernetes-list-map-keys", "sets x-kubernetes-list-type=map and must set x-kubernetes-list-map-keys")
+				}
+				if items == nil || items.Type != "object" {
+					report(simpleLocation, "items", fmt.Sprintf("sets x-kubernetes-list-type=map but its items are of type %q, only arrays of objects can be keyed", itemsType(items)))
+					continue
+				}
+				if items.Nullable {
+					report(itemsLocation, "nullable", "may not be nullable because the list sets x-kubernetes-list-type=map")
+				}
+
+				required := sets.NewString(items.Required...)
+				seenKeys := sets.NewString()
+				for _, key := range s.XListMapKeys {
+					keyLocation := itemsLocation + "." + key
+					if seenKeys.Has(key) {
+						report(simpleLocation, "x-kubernetes-list-map-keys/"+key, fmt.Sprintf("lists %v more than once in x-kubernetes-list-map-keys", key))
+						continue
+					}
+					seenKeys.Insert(key)
+
+					keySchema, ok := items.Properties[key]
+					if !ok {
+						report(simpleLocation, "x-kubernetes-list-map-keys/"+key, fmt.Sprintf("lists %v in x-kubernetes-list-map-keys but its items have no such property", key))
+						continue
+					}
+					if keySchema.Type == "array" || keySchema.Type == "object" {
+						report(keyLocation, "type", fmt.Sprintf("is a list map key and must be a scalar, not %v", keySchema.Type))
+					}
+					if !required.Has(key) && keySchema.Default == nil {
+						report(keyLocation, "required", "is a list map key and must be required or have a default")
+					}
+					if keySchema.Nullable {
+						report(keyLocation, "nullable", "is a list map key and may not be nullable")
+					}
+				}
+			case "set":
+				if items == nil {
+					continue
+				}
+				if items.Nullable {
+					report(itemsLocation, "nullable", "may not be nullable because the list sets x-kubernetes-list-type=set")
+				}
+				switch items.Type {
+				case "object":
+					if stringOrEmpty(items.XMapType) != "atomic" {
+						report(itemsLocation, "x-kubernetes-map-type", "must set x-kubernetes-map-type=atomic because the list sets x-kubernetes-list-type=set")
+					}
+				case "array":
+					if itemsListType := stringOrEmpty(items.XListType); itemsListType != "" && itemsListType != "atomic" {
+						report(itemsLocation, "x-kubernetes-list-type", fmt.Sprintf("sets x-kubernetes-list-type=%v but must be atomic because the list sets x-kubernetes-list-type=set", itemsListType))
+					}
+				}
+			default:
+				report(simpleLocation, "x-kubernetes-list-type", fmt.Sprintf("sets unsupported x-kubernetes-list-type=%v, it must be atomic, set, or map", listType))
+			}
+		}
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
+}
+
+func (b listTypesMustBeValid) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	return RatchetCompare(b, existingCRD, newCRD)
+}
+
+func itemsType(items *apiextensionsv1.JSONSchemaProps) string {
+	if items == nil {
+		return ""
+	}
+	return items.Type
+}
@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestListTypesMustBeValid(t *testing.T) {
+	RunAllTestsInDirForComparator(t, ListTypesMustBeValid(), "testdata/list_types_must_be_valid")
+}
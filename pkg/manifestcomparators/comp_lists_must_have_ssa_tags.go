@@ -24,7 +24,7 @@ func (listsMustHaveSSATags) WhyItMatters() string {
 }
 
 func (b listsMustHaveSSATags) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
-	errsToReport := []string{}
+	errsToReport := []Finding{}
 
 	for _, newVersion := range crd.Spec.Versions {
 		fieldsWithoutListType := []string{}
@@ -40,19 +40,12 @@ func (b listsMustHaveSSATags) Validate(crd *apiextensionsv1.CustomResourceDefini
 			})
 
 		for _, newMapField := range fieldsWithoutListType {
-			errsToReport = append(errsToReport, fmt.Sprintf("crd/%v version/%v field/%v must set x-kubernetes-list-type", crd.Name, newVersion.Name, newMapField))
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, newMapField, fmt.Sprintf("crd/%v version/%v field/%v must set x-kubernetes-list-type", crd.Name, newVersion.Name, newMapField)))
 		}
 
 	}
 
-	return ComparisonResults{
-		Name:         b.Name(),
-		WhyItMatters: b.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: nil,
-		Infos:    nil,
-	}, nil
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
 }
 
 func (b listsMustHaveSSATags) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
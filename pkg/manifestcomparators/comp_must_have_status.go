@@ -24,7 +24,7 @@ func (mustHaveStatus) WhyItMatters() string {
 
 func (b mustHaveStatus) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
 	const statusField = "^.status"
-	errsToReport := []string{}
+	errsToReport := []Finding{}
 
 	for _, newVersion := range crd.Spec.Versions {
 		if newVersion.Subresources != nil && newVersion.Subresources.Status != nil {
@@ -42,19 +42,12 @@ func (b mustHaveStatus) Validate(crd *apiextensionsv1.CustomResourceDefinition)
 			})
 
 		if hasStatus {
-			errsToReport = append(errsToReport, fmt.Sprintf("crd/%v version/%v field/%v must have a status subresource in .spec.version[name=%v].subresources.status to match its schema.", crd.Name, newVersion.Name, statusField, newVersion.Name))
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, statusField, fmt.Sprintf("crd/%v version/%v field/%v must have a status subresource in .spec.version[name=%v].subresources.status to match its schema.", crd.Name, newVersion.Name, statusField, newVersion.Name)))
 		}
 
 	}
 
-	return ComparisonResults{
-		Name:         b.Name(),
-		WhyItMatters: b.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: nil,
-		Infos:    nil,
-	}, nil
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
 }
 
 func (b mustHaveStatus) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
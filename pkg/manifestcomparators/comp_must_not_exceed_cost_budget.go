@@ -18,10 +18,18 @@ import (
 	"k8s.io/apiserver/pkg/cel/environment"
 )
 
-type mustNotExceedCostBudget struct{}
+type mustNotExceedCostBudget struct {
+	kubeVersions KubeVersionRange
+}
 
 func MustNotExceedCostBudget() CRDComparator {
-	return mustNotExceedCostBudget{}
+	return MustNotExceedCostBudgetForKubeVersions(DefaultKubeVersionRange())
+}
+
+// MustNotExceedCostBudgetForKubeVersions compiles rules with the CEL libraries of the oldest version of kubeVersions
+// and reports rules that only compile with the libraries of the newest version.
+func MustNotExceedCostBudgetForKubeVersions(kubeVersions KubeVersionRange) CRDComparator {
+	return mustNotExceedCostBudget{kubeVersions: kubeVersions}
 }
 
 func (mustNotExceedCostBudget) Name() string {
@@ -33,14 +41,14 @@ func (mustNotExceedCostBudget) WhyItMatters() string {
 }
 
 func (b mustNotExceedCostBudget) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
-	errsToReport := []string{}
-	warnings := []string{}
-	infos := []string{}
+	errsToReport := []Finding{}
+	warnings := []Finding{}
+	infos := []Finding{}
 
 	for _, newVersion := range crd.Spec.Versions {
 		schema := &apiextensions.JSONSchemaProps{}
 		if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(newVersion.Schema.OpenAPIV3Schema, schema, nil); err != nil {
-			errsToReport = append(errsToReport, err.Error())
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, "", err.Error()))
 			continue
 		}
 
@@ -50,7 +58,7 @@ func (b mustNotExceedCostBudget) Validate(crd *apiextensionsv1.CustomResourceDef
 			func(s *apiextensionsv1.JSONSchemaProps, fldPath, simpleLocation *field.Path, ancestry []*apiextensionsv1.JSONSchemaProps) bool {
 				schema := &apiextensions.JSONSchemaProps{}
 				if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(s, schema, nil); err != nil {
-					errsToReport = append(errsToReport, err.Error())
+					errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), err.Error()))
 					return false
 				}
 
@@ -61,20 +69,24 @@ func (b mustNotExceedCostBudget) Validate(crd *apiextensionsv1.CustomResourceDef
 
 				schemaInfos, schemaWarnings, err := inspectSchema(schema, simpleLocation, len(ancestry) == 0)
 				if err != nil {
-					errsToReport = append(errsToReport, err.Error())
+					errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), err.Error()))
+				}
+				for _, schemaInfo := range schemaInfos {
+					infos = append(infos, NewInfo(crd.Name, newVersion.Name, simpleLocation.String(), schemaInfo))
+				}
+				for _, schemaWarning := range schemaWarnings {
+					warnings = append(warnings, NewWarning(crd.Name, newVersion.Name, simpleLocation.String(), schemaWarning))
 				}
-				infos = append(infos, schemaInfos...)
-				warnings = append(warnings, schemaWarnings...)
 
 				celContext, err := extractCELContext(append(ancestry, s), fldPath)
 				if err != nil {
-					errsToReport = append(errsToReport, err.Error())
+					errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), err.Error()))
 					return false
 				}
 
 				typeInfo, err := celContext.TypeInfo()
 				if err != nil {
-					errsToReport = append(errsToReport, err.Error())
+					errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), err.Error()))
 					return false
 				}
 
@@ -87,57 +99,85 @@ func (b mustNotExceedCostBudget) Validate(crd *apiextensionsv1.CustomResourceDef
 					typeInfo.Schema,
 					typeInfo.DeclType,
 					celconfig.PerCallLimit,
-					environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion()),
+					environment.MustBaseEnvSet(b.kubeVersions.Oldest),
 					cel.NewExpressionsEnvLoader(),
 				)
 				if err != nil {
 					fieldErr := field.InternalError(fldPath, fmt.Errorf("failed to compile x-kubernetes-validations rules: %w", err))
-					errsToReport = append(errsToReport, fieldErr.Error())
+					errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), fieldErr.Error()))
 					return false
 				}
+				// rules that only compile with the libraries of newer versions are rejected by the oldest apiserver.
+				var newestCompResults []cel.CompilationResult
+				if !b.kubeVersions.Oldest.EqualTo(b.kubeVersions.Newest) {
+					newestCompResults, err = cel.Compile(
+						typeInfo.Schema,
+						typeInfo.DeclType,
+						celconfig.PerCallLimit,
+						environment.MustBaseEnvSet(b.kubeVersions.Newest),
+						cel.NewExpressionsEnvLoader(),
+					)
+					if err != nil {
+						fieldErr := field.InternalError(fldPath, fmt.Errorf("failed to compile x-kubernetes-validations rules: %w", err))
+						errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), fieldErr.Error()))
+						return false
+					}
+				}
+				unavailableOnOldest := func(detail string) string {
+					return fmt.Sprintf("compiles with the libraries of Kubernetes %v but not with those of Kubernetes %v, the oldest target: %v", b.kubeVersions.Newest, b.kubeVersions.Oldest, detail)
+				}
 
-				for i, cr := range compResults {
+				// the cardinality belongs to the field, not to a rule, so it is reported once for all of its rules.
+				if len(compResults) > 0 {
 					if celContext.MaxCardinality == nil {
 						unboundedParents, err := getUnboundedParentFields(ancestry, fldPath)
 						if err != nil {
-							errsToReport = append(errsToReport, err.Error())
+							errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), err.Error()).WithDiscriminator("cardinality"))
 						}
-						warnings = append(warnings, fmt.Sprintf("%s: Field has unbounded cardinality. At least one, variable parent field does not have a maxItems or maxProperties constraint: %s. Falling back to CEL calculated worst case of %d executions.", simpleLocation.String(), strings.Join(unboundedParents, ","), cr.MaxCardinality))
+						warnings = append(warnings, NewWarning(crd.Name, newVersion.Name, simpleLocation.String(), fmt.Sprintf("%s: Field has unbounded cardinality. At least one, variable parent field does not have a maxItems or maxProperties constraint: %s. Falling back to CEL calculated worst case of %d executions.", simpleLocation.String(), strings.Join(unboundedParents, ","), compResults[0].MaxCardinality)).WithDiscriminator("cardinality"))
 					} else {
 						msg := fmt.Sprintf("%s: Field has a maximum cardinality of %d.", simpleLocation.String(), *celContext.MaxCardinality)
 						if *celContext.MaxCardinality > 1 {
 							msg += " This is the calculated, worst case number of times the rule will be evaluated."
 						}
 
-						infos = append(infos, msg)
+						infos = append(infos, NewInfo(crd.Name, newVersion.Name, simpleLocation.String(), msg).WithDiscriminator("cardinality"))
 					}
+				}
 
+				for i, cr := range compResults {
+					// a field can have several rules, the findings of each rule are told apart by its index.
+					rule := fmt.Sprintf("rule[%d]", i)
 					expressionCost := getExpressionCost(cr, celContext)
 
 					if expressionCost > apiextensionsvalidation.StaticEstimatedCostLimit {
 						costErrorMsg := getCostErrorMessage("estimated rule cost", expressionCost, apiextensionsvalidation.StaticEstimatedCostLimit)
-						errsToReport = append(errsToReport, field.Forbidden(fldPath, costErrorMsg).Error())
+						errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), field.Forbidden(fldPath, costErrorMsg).Error()).WithDiscriminator(rule+".cost"))
 					}
 					if rootCELContext.TotalCost != nil {
 						rootCELContext.TotalCost.ObserveExpressionCost(fldPath, expressionCost)
 					}
 
 					if cr.Error != nil {
-						if cr.Error.Type == apiservercel.ErrorTypeRequired {
-							errsToReport = append(errsToReport, field.Required(fldPath, cr.Error.Detail).Error())
+						if newestCompResults != nil && newestCompResults[i].Error == nil {
+							errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), field.Invalid(fldPath, schema.XValidations[i], unavailableOnOldest(cr.Error.Detail)).Error()).WithDiscriminator(rule))
+						} else if cr.Error.Type == apiservercel.ErrorTypeRequired {
+							errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), field.Required(fldPath, cr.Error.Detail).Error()).WithDiscriminator(rule))
 						} else {
-							errsToReport = append(errsToReport, field.Invalid(fldPath, schema.XValidations[i], cr.Error.Detail).Error())
+							errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), field.Invalid(fldPath, schema.XValidations[i], cr.Error.Detail).Error()).WithDiscriminator(rule))
 						}
 					} else {
-						infos = append(infos, fmt.Sprintf("%s: Rule %d raw cost is %d. Estimated total cost of %d. The maximum allowable value is %d. Rule is %.2f%% of allowed budget.", simpleLocation.String(), i, cr.MaxCost, expressionCost, apiextensionsvalidation.StaticEstimatedCostLimit, float64(expressionCost*100)/apiextensionsvalidation.StaticEstimatedCostLimit))
+						infos = append(infos, NewInfo(crd.Name, newVersion.Name, simpleLocation.String(), fmt.Sprintf("%s: Rule %d raw cost is %d. Estimated total cost of %d. The maximum allowable value is %d. Rule is %.2f%% of allowed budget.", simpleLocation.String(), i, cr.MaxCost, expressionCost, apiextensionsvalidation.StaticEstimatedCostLimit, float64(expressionCost*100)/apiextensionsvalidation.StaticEstimatedCostLimit)).WithDiscriminator(rule+".cost"))
 					}
 
-					if cr.MessageExpressionError != nil {
-						errsToReport = append(errsToReport, field.Invalid(fldPath, schema.XValidations[i], cr.MessageExpressionError.Detail).Error())
+					if cr.MessageExpressionError != nil && newestCompResults != nil && newestCompResults[i].MessageExpressionError == nil {
+						errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), field.Invalid(fldPath, schema.XValidations[i], unavailableOnOldest(cr.MessageExpressionError.Detail)).Error()).WithDiscriminator(rule+".messageExpression"))
+					} else if cr.MessageExpressionError != nil {
+						errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), field.Invalid(fldPath, schema.XValidations[i], cr.MessageExpressionError.Detail).Error()).WithDiscriminator(rule+".messageExpression"))
 					} else if cr.MessageExpression != nil {
 						if cr.MessageExpressionMaxCost > apiextensionsvalidation.StaticEstimatedCostLimit {
 							costErrorMsg := getCostErrorMessage("estimated messageExpression cost", cr.MessageExpressionMaxCost, apiextensionsvalidation.StaticEstimatedCostLimit)
-							errsToReport = append(errsToReport, field.Forbidden(fldPath, costErrorMsg).Error())
+							errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, simpleLocation.String(), field.Forbidden(fldPath, costErrorMsg).Error()).WithDiscriminator(rule+".messageExpression.cost"))
 						}
 						if celContext.TotalCost != nil {
 							celContext.TotalCost.ObserveExpressionCost(fldPath, cr.MessageExpressionMaxCost)
@@ -150,18 +190,13 @@ func (b mustNotExceedCostBudget) Validate(crd *apiextensionsv1.CustomResourceDef
 
 		if rootCELContext != nil && rootCELContext.TotalCost != nil && rootCELContext.TotalCost.Total > apiextensionsvalidation.StaticEstimatedCRDCostLimit {
 			costErrorMsg := getCostErrorMessage("total CRD cost", rootCELContext.TotalCost.Total, apiextensionsvalidation.StaticEstimatedCRDCostLimit)
-			errsToReport = append(errsToReport, field.Forbidden(field.NewPath("^"), costErrorMsg).Error())
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, "^", field.Forbidden(field.NewPath("^"), costErrorMsg).Error()).WithDiscriminator("totalCost"))
 		}
 	}
 
-	return ComparisonResults{
-		Name:         b.Name(),
-		WhyItMatters: b.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: warnings,
-		Infos:    infos,
-	}, nil
+	findings := append(errsToReport, warnings...)
+	findings = append(findings, infos...)
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), findings), nil
 }
 
 func (b mustNotExceedCostBudget) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
@@ -25,7 +25,7 @@ func (noBools) WhyItMatters() string {
 }
 
 func (b noBools) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
-	errsToReport := []string{}
+	errsToReport := []Finding{}
 
 	for _, newVersion := range crd.Spec.Versions {
 		newBoolFields := []string{}
@@ -38,19 +38,12 @@ func (b noBools) Validate(crd *apiextensionsv1.CustomResourceDefinition) (Compar
 			})
 
 		for _, newBoolField := range newBoolFields {
-			errsToReport = append(errsToReport, fmt.Sprintf("crd/%v version/%v field/%v may not be a boolean", crd.Name, newVersion.Name, newBoolField))
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, newBoolField, fmt.Sprintf("crd/%v version/%v field/%v may not be a boolean", crd.Name, newVersion.Name, newBoolField)))
 		}
 
 	}
 
-	return ComparisonResults{
-		Name:         b.Name(),
-		WhyItMatters: b.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: nil,
-		Infos:    nil,
-	}, nil
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
 }
 
 func (b noBools) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
@@ -0,0 +1,34 @@
+package manifestcomparators
+
+import (
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+type noCRDRemoval struct{}
+
+// NoCRDRemoval reports a CRD that exists in the existing manifests but not in the new ones.  It is the only comparator
+// that accepts a nil newCRD.
+func NoCRDRemoval() CRDComparator {
+	return noCRDRemoval{}
+}
+
+func (noCRDRemoval) Name() string {
+	return "NoCRDRemoval"
+}
+
+func (noCRDRemoval) WhyItMatters() string {
+	return "Removing a CRD deletes every custom resource of that type from the cluster and breaks every " +
+		"client that still uses the API."
+}
+
+func (b noCRDRemoval) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	if existingCRD == nil || newCRD != nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), []Finding{
+		NewError(existingCRD.Name, "", "", fmt.Sprintf("crd/%v may not be removed", existingCRD.Name)),
+	}), nil
+}
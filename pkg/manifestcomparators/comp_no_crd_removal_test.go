@@ -0,0 +1,30 @@
+package manifestcomparators
+
+import (
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNoCRDRemoval(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "schedulers.config.openshift.io"}}
+
+	removed, err := NoCRDRemoval().Compare(crd, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed.Errors) != 1 || removed.Errors[0] != "crd/schedulers.config.openshift.io may not be removed" {
+		t.Errorf("unexpected errors for a removed crd: %v", removed.Errors)
+	}
+
+	for _, pair := range [][2]*apiextensionsv1.CustomResourceDefinition{{nil, crd}, {crd, crd}} {
+		results, err := NoCRDRemoval().Compare(pair[0], pair[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results.Findings) != 0 {
+			t.Errorf("unexpected findings: %v", results.Findings)
+		}
+	}
+}
@@ -53,16 +53,9 @@ func getChangedTypes(existingFieldsAndTypes map[string]string, newFieldsAndTypes
 
 func (b noDataTypeChange) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
 	if existingCRD == nil {
-		return ComparisonResults{
-			Name:         b.Name(),
-			WhyItMatters: b.WhyItMatters(),
-
-			Errors:   nil,
-			Warnings: nil,
-			Infos:    nil,
-		}, nil
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
 	}
-	errsToReport := []string{}
+	errsToReport := []Finding{}
 
 	for _, newVersion := range newCRD.Spec.Versions {
 		existingVersion := GetVersionByName(existingCRD, newVersion.Name)
@@ -75,16 +68,10 @@ func (b noDataTypeChange) Compare(existingCRD, newCRD *apiextensionsv1.CustomRes
 
 		changedTypes := getChangedTypes(existingFieldsAndTypes, newFieldsAndTypes)
 		for changedField, changedType := range changedTypes {
-			errsToReport = append(errsToReport, fmt.Sprintf("crd/%v version/%v data type of field/%v may not be changed from %v to %v", newCRD.Name, newVersion.Name, changedField, changedType.ExistingType, changedType.NewType))
+			msg := fmt.Sprintf("crd/%v version/%v data type of field/%v may not be changed from %v to %v", newCRD.Name, newVersion.Name, changedField, changedType.ExistingType, changedType.NewType)
+			errsToReport = append(errsToReport, NewError(newCRD.Name, newVersion.Name, changedField, msg).WithValues(changedType.ExistingType, changedType.NewType))
 		}
 	}
 
-	return ComparisonResults{
-		Name:         b.Name(),
-		WhyItMatters: b.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: nil,
-		Infos:    nil,
-	}, nil
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
 }
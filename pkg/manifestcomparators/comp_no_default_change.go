@@ -0,0 +1,89 @@
+package manifestcomparators
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+type noDefaultChange struct{}
+
+func NoDefaultChange() CRDComparator {
+	return noDefaultChange{}
+}
+
+func (noDefaultChange) Name() string {
+	return "NoDefaultChange"
+}
+
+func (noDefaultChange) WhyItMatters() string {
+	return "If a default is added or changed, then the apiserver persists a different value on the next write of " +
+		"existing objects, which silently changes their behavior.  If a default is removed, then new objects no longer " +
+		"get a value that clients may rely on."
+}
+
+// defaultValue returns the default of the schema decoded for semantic comparison and rendered as canonical JSON, with
+// sorted keys and without insignificant whitespace.
+func defaultValue(s *apiextensionsv1.JSONSchemaProps) (interface{}, string, bool, error) {
+	if s.Default == nil || len(s.Default.Raw) == 0 {
+		return nil, "", false, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(s.Default.Raw, &value); err != nil {
+		return nil, "", false, fmt.Errorf("cannot decode default %v: %w", string(s.Default.Raw), err)
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return value, string(canonical), true, nil
+}
+
+func (b noDefaultChange) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	if existingCRD == nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
+	}
+	findings := []Finding{}
+
+	for _, newVersion := range newCRD.Spec.Versions {
+		existingVersion := GetVersionByName(existingCRD, newVersion.Name)
+		if existingVersion == nil {
+			continue
+		}
+
+		existingSchemas := getSchemasByPath(existingVersion)
+		newSchemas := getSchemasByPath(&newVersion)
+
+		for _, path := range sharedSchemaPaths(existingSchemas, newSchemas) {
+			existingSchema, newSchema := existingSchemas[path].schema, newSchemas[path].schema
+			simpleLocation := newSchemas[path].simpleLocation
+
+			existingDefault, existingJSON, existingSet, err := defaultValue(&existingSchema)
+			if err != nil {
+				return ComparisonResults{}, fmt.Errorf("crd/%v version/%v field/%v: %w", existingCRD.Name, newVersion.Name, simpleLocation, err)
+			}
+			newDefault, newJSON, newSet, err := defaultValue(&newSchema)
+			if err != nil {
+				return ComparisonResults{}, fmt.Errorf("crd/%v version/%v field/%v: %w", newCRD.Name, newVersion.Name, simpleLocation, err)
+			}
+
+			prefix := fmt.Sprintf("crd/%v version/%v field/%v", newCRD.Name, newVersion.Name, simpleLocation)
+			switch {
+			case !existingSet && !newSet:
+			case !existingSet:
+				findings = append(findings, NewError(newCRD.Name, newVersion.Name, simpleLocation,
+					fmt.Sprintf("%v default may not be added with value %v", prefix, newJSON)).WithValues("", newJSON))
+			case !newSet:
+				findings = append(findings, NewWarning(newCRD.Name, newVersion.Name, simpleLocation,
+					fmt.Sprintf("%v default %v was removed", prefix, existingJSON)).WithValues(existingJSON, ""))
+			case !reflect.DeepEqual(existingDefault, newDefault):
+				findings = append(findings, NewError(newCRD.Name, newVersion.Name, simpleLocation,
+					fmt.Sprintf("%v default may not be changed from %v to %v", prefix, existingJSON, newJSON)).WithValues(existingJSON, newJSON))
+			}
+		}
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), findings), nil
+}
@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestNoDefaultChange(t *testing.T) {
+	RunAllTestsInDirForComparator(t, NoDefaultChange(), "testdata/no_default_change")
+}
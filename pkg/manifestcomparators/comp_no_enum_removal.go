@@ -4,8 +4,6 @@ import (
 	"fmt"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 type noEnumRemoval struct{}
@@ -22,35 +20,11 @@ func (noEnumRemoval) WhyItMatters() string {
 	return "If enums are removed, then clients that use those enum values will not be able to upgrade to the newest CRD."
 }
 
-func getEnums(version *apiextensionsv1.CustomResourceDefinitionVersion) map[string]sets.String {
-	enumsMap := make(map[string]sets.String)
-	SchemaHas(version.Schema.OpenAPIV3Schema, field.NewPath("^"), field.NewPath("^"), nil,
-		func(s *apiextensionsv1.JSONSchemaProps, fldPath, simpleLocation *field.Path, _ []*apiextensionsv1.JSONSchemaProps) bool {
-			for _, enum := range s.Enum {
-				_, exists := enumsMap[simpleLocation.String()]
-				if !exists {
-					enumsMap[simpleLocation.String()] = sets.NewString()
-				}
-				enumsMap[simpleLocation.String()].Insert(string(enum.Raw))
-			}
-			return false
-		})
-
-	return enumsMap
-}
-
 func (b noEnumRemoval) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
 	if existingCRD == nil {
-		return ComparisonResults{
-			Name:         b.Name(),
-			WhyItMatters: b.WhyItMatters(),
-
-			Errors:   nil,
-			Warnings: nil,
-			Infos:    nil,
-		}, nil
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
 	}
-	errsToReport := []string{}
+	errsToReport := []Finding{}
 
 	for _, newVersion := range newCRD.Spec.Versions {
 
@@ -59,27 +33,26 @@ func (b noEnumRemoval) Compare(existingCRD, newCRD *apiextensionsv1.CustomResour
 			continue
 		}
 
-		existingEnumsMap := getEnums(existingVersion)
-		newEnumsMap := getEnums(&newVersion)
-
-		for field, existingEnums := range existingEnumsMap {
-			newEnums, exists := newEnumsMap[field]
-			if exists {
-				removedEnums := existingEnums.Difference(newEnums)
-				for _, removedEnum := range removedEnums.List() {
-					errsToReport = append(errsToReport, fmt.Sprintf("crd/%v version/%v enum/%v may not be removed for field/%v", newCRD.Name, newVersion.Name, removedEnum, field))
-				}
+		// schemas are compared by full path, so enums of items and of additionalProperties, which share the simple
+		// location [*], are kept apart.
+		existingSchemas := getSchemasByPath(existingVersion)
+		newSchemas := getSchemasByPath(&newVersion)
+
+		for _, path := range sharedSchemaPaths(existingSchemas, newSchemas) {
+			existingSchema, newSchema := existingSchemas[path].schema, newSchemas[path].schema
+			field := newSchemas[path].simpleLocation
+			newEnums := enumValues(&newSchema)
+			if newEnums.Len() == 0 {
+				// removing the whole enum allows every value and is reported by NoValidationLoosening.
+				continue
+			}
+			for _, removedEnum := range enumValues(&existingSchema).Difference(newEnums).List() {
+				msg := fmt.Sprintf("crd/%v version/%v enum/%v may not be removed for field/%v", newCRD.Name, newVersion.Name, removedEnum, field)
+				errsToReport = append(errsToReport, NewError(newCRD.Name, newVersion.Name, field, msg).WithValues(removedEnum, ""))
 			}
 		}
 
 	}
 
-	return ComparisonResults{
-		Name:         b.Name(),
-		WhyItMatters: b.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: nil,
-		Infos:    nil,
-	}, nil
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
 }
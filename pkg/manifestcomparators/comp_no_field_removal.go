@@ -35,16 +35,9 @@ func getFields(version *apiextensionsv1.CustomResourceDefinitionVersion) sets.St
 
 func (b noFieldRemoval) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
 	if existingCRD == nil {
-		return ComparisonResults{
-			Name:         b.Name(),
-			WhyItMatters: b.WhyItMatters(),
-
-			Errors:   nil,
-			Warnings: nil,
-			Infos:    nil,
-		}, nil
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
 	}
-	errsToReport := []string{}
+	errsToReport := []Finding{}
 
 	for _, newVersion := range newCRD.Spec.Versions {
 
@@ -58,17 +51,10 @@ func (b noFieldRemoval) Compare(existingCRD, newCRD *apiextensionsv1.CustomResou
 
 		removedFields := existingFields.Difference(newFields)
 		for _, removedField := range removedFields.List() {
-			errsToReport = append(errsToReport, fmt.Sprintf("crd/%v version/%v field/%v may not be removed", newCRD.Name, newVersion.Name, removedField))
+			errsToReport = append(errsToReport, NewError(newCRD.Name, newVersion.Name, removedField, fmt.Sprintf("crd/%v version/%v field/%v may not be removed", newCRD.Name, newVersion.Name, removedField)))
 		}
 
 	}
 
-	return ComparisonResults{
-		Name:         b.Name(),
-		WhyItMatters: b.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: nil,
-		Infos:    nil,
-	}, nil
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
 }
@@ -23,7 +23,7 @@ func (noFloats) WhyItMatters() string {
 }
 
 func (b noFloats) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
-	errsToReport := []string{}
+	errsToReport := []Finding{}
 
 	for _, newVersion := range crd.Spec.Versions {
 		newFloatFields := []string{}
@@ -36,19 +36,12 @@ func (b noFloats) Validate(crd *apiextensionsv1.CustomResourceDefinition) (Compa
 			})
 
 		for _, newFloatField := range newFloatFields {
-			errsToReport = append(errsToReport, fmt.Sprintf("crd/%v version/%v field/%v may not be a float", crd.Name, newVersion.Name, newFloatField))
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, newFloatField, fmt.Sprintf("crd/%v version/%v field/%v may not be a float", crd.Name, newVersion.Name, newFloatField)))
 		}
 
 	}
 
-	return ComparisonResults{
-		Name:         b.Name(),
-		WhyItMatters: b.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: nil,
-		Infos:    nil,
-	}, nil
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
 }
 
 func (b noFloats) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
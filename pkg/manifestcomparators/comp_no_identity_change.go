@@ -0,0 +1,64 @@
+package manifestcomparators
+
+import (
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type noIdentityChange struct{}
+
+func NoIdentityChange() CRDComparator {
+	return noIdentityChange{}
+}
+
+func (noIdentityChange) Name() string {
+	return "NoIdentityChange"
+}
+
+func (noIdentityChange) WhyItMatters() string {
+	return "If the group, scope, kind, or resource names change, then every existing client, RBAC rule, and stored " +
+		"reference to the resource stops working.  If short names or categories are removed, then kubectl aliases and " +
+		"groupings like 'kubectl get all' that users rely on stop including the resource."
+}
+
+func (b noIdentityChange) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	if existingCRD == nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
+	}
+	findings := []Finding{}
+
+	identity := []struct {
+		name                    string
+		existingValue, newValue string
+	}{
+		{name: "spec.group", existingValue: existingCRD.Spec.Group, newValue: newCRD.Spec.Group},
+		{name: "spec.scope", existingValue: string(existingCRD.Spec.Scope), newValue: string(newCRD.Spec.Scope)},
+		{name: "spec.names.kind", existingValue: existingCRD.Spec.Names.Kind, newValue: newCRD.Spec.Names.Kind},
+		{name: "spec.names.listKind", existingValue: existingCRD.Spec.Names.ListKind, newValue: newCRD.Spec.Names.ListKind},
+		{name: "spec.names.plural", existingValue: existingCRD.Spec.Names.Plural, newValue: newCRD.Spec.Names.Plural},
+		{name: "spec.names.singular", existingValue: existingCRD.Spec.Names.Singular, newValue: newCRD.Spec.Names.Singular},
+	}
+	for _, curr := range identity {
+		// the existing CRD is empty when it is created through admission.
+		if len(curr.existingValue) == 0 || curr.existingValue == curr.newValue {
+			continue
+		}
+		msg := fmt.Sprintf("crd/%v %v may not be changed from %v to %v", newCRD.Name, curr.name, curr.existingValue, curr.newValue)
+		findings = append(findings, NewError(newCRD.Name, "", "", msg).WithValues(curr.existingValue, curr.newValue).WithDiscriminator(curr.name))
+	}
+
+	removedShortNames := sets.NewString(existingCRD.Spec.Names.ShortNames...).Difference(sets.NewString(newCRD.Spec.Names.ShortNames...))
+	for _, shortName := range removedShortNames.List() {
+		msg := fmt.Sprintf("crd/%v shortName %v was removed, 'kubectl get %v' will no longer work", newCRD.Name, shortName, shortName)
+		findings = append(findings, NewWarning(newCRD.Name, "", "", msg).WithValues(shortName, "").WithDiscriminator("spec.names.shortNames"))
+	}
+	removedCategories := sets.NewString(existingCRD.Spec.Names.Categories...).Difference(sets.NewString(newCRD.Spec.Names.Categories...))
+	for _, category := range removedCategories.List() {
+		msg := fmt.Sprintf("crd/%v category %v was removed, 'kubectl get %v' will no longer list %v", newCRD.Name, category, category, newCRD.Spec.Names.Plural)
+		findings = append(findings, NewWarning(newCRD.Name, "", "", msg).WithValues(category, "").WithDiscriminator("spec.names.categories"))
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), findings), nil
+}
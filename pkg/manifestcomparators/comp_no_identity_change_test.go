@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestNoIdentityChange(t *testing.T) {
+	RunAllTestsInDirForComparator(t, NoIdentityChange(), "testdata/no_identity_change")
+}
@@ -25,7 +25,7 @@ func (noMaps) WhyItMatters() string {
 }
 
 func (b noMaps) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
-	errsToReport := []string{}
+	errsToReport := []Finding{}
 
 	for _, newVersion := range crd.Spec.Versions {
 		newMapFields := []string{}
@@ -42,19 +42,12 @@ func (b noMaps) Validate(crd *apiextensionsv1.CustomResourceDefinition) (Compari
 			})
 
 		for _, newMapField := range newMapFields {
-			errsToReport = append(errsToReport, fmt.Sprintf("crd/%v version/%v field/%v may not be a map", crd.Name, newVersion.Name, newMapField))
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, newMapField, fmt.Sprintf("crd/%v version/%v field/%v may not be a map", crd.Name, newVersion.Name, newMapField)))
 		}
 
 	}
 
-	return ComparisonResults{
-		Name:         b.Name(),
-		WhyItMatters: b.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: nil,
-		Infos:    nil,
-	}, nil
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
 }
 
 func (b noMaps) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
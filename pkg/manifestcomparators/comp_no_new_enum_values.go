@@ -0,0 +1,57 @@
+package manifestcomparators
+
+import (
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+type noNewEnumValues struct{}
+
+func NoNewEnumValues() CRDComparator {
+	return noNewEnumValues{}
+}
+
+func (noNewEnumValues) Name() string {
+	return "NoNewEnumValues"
+}
+
+func (noNewEnumValues) WhyItMatters() string {
+	return "If enum values are added, then clients that switch exhaustively over the known values will not be able to " +
+		"handle objects that use the new values."
+}
+
+func (b noNewEnumValues) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	if existingCRD == nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
+	}
+	warnings := []Finding{}
+
+	for _, newVersion := range newCRD.Spec.Versions {
+		existingVersion := GetVersionByName(existingCRD, newVersion.Name)
+		if existingVersion == nil {
+			continue
+		}
+
+		// schemas are matched by full path so that items and additionalProperties, which share a simple location, are
+		// compared separately.  Fields that are new to the version are skipped, every value on them is new.
+		existingSchemas := getSchemasByPath(existingVersion)
+		newSchemas := getSchemasByPath(&newVersion)
+
+		for _, path := range sharedSchemaPaths(existingSchemas, newSchemas) {
+			existingSchema, newSchema := existingSchemas[path].schema, newSchemas[path].schema
+			existingEnums := enumValues(&existingSchema)
+			if existingEnums.Len() == 0 {
+				// an enum added to an existing field restricts values instead of adding them.
+				continue
+			}
+			simpleLocation := newSchemas[path].simpleLocation
+			for _, addedEnum := range enumValues(&newSchema).Difference(existingEnums).List() {
+				msg := fmt.Sprintf("crd/%v version/%v enum/%v was added for field/%v", newCRD.Name, newVersion.Name, addedEnum, simpleLocation)
+				warnings = append(warnings, NewWarning(newCRD.Name, newVersion.Name, simpleLocation, msg).WithValues("", addedEnum))
+			}
+		}
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), warnings), nil
+}
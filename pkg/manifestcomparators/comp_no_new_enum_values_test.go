@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestNoNewEnumValues(t *testing.T) {
+	RunAllTestsInDirForComparator(t, NoNewEnumValues(), "testdata/no_new_enum_values")
+}
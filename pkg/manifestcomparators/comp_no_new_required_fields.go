@@ -53,16 +53,9 @@ func isFieldOptional(
 
 func (b noNewRequiredFields) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
 	if existingCRD == nil {
-		return ComparisonResults{
-			Name:         b.Name(),
-			WhyItMatters: b.WhyItMatters(),
-
-			Errors:   nil,
-			Warnings: nil,
-			Infos:    nil,
-		}, nil
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
 	}
-	errsToReport := []string{}
+	errsToReport := []Finding{}
 
 	for _, newVersion := range newCRD.Spec.Versions {
 
@@ -153,19 +146,12 @@ func (b noNewRequiredFields) Compare(existingCRD, newCRD *apiextensionsv1.Custom
 			})
 
 		for _, newRequiredField := range newRequiredFields.List() {
-			errsToReport = append(errsToReport, fmt.Sprintf("crd/%v version/%v field/%v is new and may not be required", newCRD.Name, newVersion.Name, newRequiredField))
+			errsToReport = append(errsToReport, NewError(newCRD.Name, newVersion.Name, newRequiredField, fmt.Sprintf("crd/%v version/%v field/%v is new and may not be required", newCRD.Name, newVersion.Name, newRequiredField)))
 		}
 
 	}
 
-	return ComparisonResults{
-		Name:         b.Name(),
-		WhyItMatters: b.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: nil,
-		Infos:    nil,
-	}, nil
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
 }
 
 // captures parent from ^.properties[spec].properties[parent]
@@ -0,0 +1,89 @@
+package manifestcomparators
+
+import (
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+type noPatternOrFormatTightening struct{}
+
+func NoPatternOrFormatTightening() CRDComparator {
+	return noPatternOrFormatTightening{}
+}
+
+func (noPatternOrFormatTightening) Name() string {
+	return "NoPatternOrFormatTightening"
+}
+
+func (noPatternOrFormatTightening) WhyItMatters() string {
+	return "If a pattern is tightened or a format is added or changed, then string values that are already stored may " +
+		"no longer be valid and clients will not be able to update those objects without first changing the value."
+}
+
+func (b noPatternOrFormatTightening) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	if existingCRD == nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
+	}
+	findings := []Finding{}
+
+	for _, newVersion := range newCRD.Spec.Versions {
+		existingVersion := GetVersionByName(existingCRD, newVersion.Name)
+		if existingVersion == nil {
+			continue
+		}
+
+		existingSchemas := getSchemasByPath(existingVersion)
+		newSchemas := getSchemasByPath(&newVersion)
+
+		for _, path := range sharedSchemaPaths(existingSchemas, newSchemas) {
+			existingSchema, newSchema := existingSchemas[path].schema, newSchemas[path].schema
+			simpleLocation := newSchemas[path].simpleLocation
+			prefix := fmt.Sprintf("crd/%v version/%v field/%v", newCRD.Name, newVersion.Name, simpleLocation)
+
+			if existingSchema.Pattern != newSchema.Pattern {
+				var finding Finding
+				// an empty pattern matches every value, so added and removed patterns are analyzed like changed ones.
+				superset, counterexample, err := patternAcceptsSuperset(existingSchema.Pattern, newSchema.Pattern)
+				switch {
+				case err != nil:
+					finding = NewWarning(newCRD.Name, newVersion.Name, simpleLocation,
+						fmt.Sprintf("%v pattern %v and cannot be checked for values that are no longer accepted: %v", prefix, describePatternChange(existingSchema.Pattern, newSchema.Pattern), err))
+				case superset:
+					finding = NewInfo(newCRD.Name, newVersion.Name, simpleLocation,
+						fmt.Sprintf("%v pattern %v and accepts every value that was accepted before", prefix, describePatternChange(existingSchema.Pattern, newSchema.Pattern)))
+				default:
+					finding = NewError(newCRD.Name, newVersion.Name, simpleLocation,
+						fmt.Sprintf("%v pattern %v and no longer accepts values like %q", prefix, describePatternChange(existingSchema.Pattern, newSchema.Pattern), counterexample))
+				}
+				findings = append(findings, finding.WithValues(existingSchema.Pattern, newSchema.Pattern).WithDiscriminator("pattern"))
+			}
+
+			switch {
+			case existingSchema.Format == newSchema.Format:
+			case len(existingSchema.Format) == 0:
+				findings = append(findings, NewError(newCRD.Name, newVersion.Name, simpleLocation,
+					fmt.Sprintf("%v format may not be added with value %v", prefix, newSchema.Format)).WithValues(existingSchema.Format, newSchema.Format).WithDiscriminator("format"))
+			case len(newSchema.Format) == 0:
+				findings = append(findings, NewInfo(newCRD.Name, newVersion.Name, simpleLocation,
+					fmt.Sprintf("%v format %v was removed", prefix, existingSchema.Format)).WithValues(existingSchema.Format, newSchema.Format).WithDiscriminator("format"))
+			default:
+				findings = append(findings, NewError(newCRD.Name, newVersion.Name, simpleLocation,
+					fmt.Sprintf("%v format may not be changed from %v to %v", prefix, existingSchema.Format, newSchema.Format)).WithValues(existingSchema.Format, newSchema.Format).WithDiscriminator("format"))
+			}
+		}
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), findings), nil
+}
+
+func describePatternChange(existingPattern, newPattern string) string {
+	switch {
+	case len(existingPattern) == 0:
+		return fmt.Sprintf("was added with value %v", newPattern)
+	case len(newPattern) == 0:
+		return fmt.Sprintf("%v was removed", existingPattern)
+	default:
+		return fmt.Sprintf("was changed from %v to %v", existingPattern, newPattern)
+	}
+}
@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestNoPatternOrFormatTightening(t *testing.T) {
+	RunAllTestsInDirForComparator(t, NoPatternOrFormatTightening(), "testdata/no_pattern_or_format_tightening")
+}
@@ -0,0 +1,61 @@
+package manifestcomparators
+
+import (
+	"fmt"
+	"strconv"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+type noPreserveUnknownFieldsOrNullableRemoval struct{}
+
+func NoPreserveUnknownFieldsOrNullableRemoval() CRDComparator {
+	return noPreserveUnknownFieldsOrNullableRemoval{}
+}
+
+func (noPreserveUnknownFieldsOrNullableRemoval) Name() string {
+	return "NoPreserveUnknownFieldsOrNullableRemoval"
+}
+
+func (noPreserveUnknownFieldsOrNullableRemoval) WhyItMatters() string {
+	return "If x-kubernetes-preserve-unknown-fields is removed, then the apiserver prunes the unknown fields of existing " +
+		"objects on their next write and the data is lost.  If nullable is removed, then existing objects with null " +
+		"values no longer validate and clients will not be able to update them."
+}
+
+func preservesUnknownFields(s *apiextensionsv1.JSONSchemaProps) bool {
+	return s.XPreserveUnknownFields != nil && *s.XPreserveUnknownFields
+}
+
+func (b noPreserveUnknownFieldsOrNullableRemoval) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	if existingCRD == nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
+	}
+	errsToReport := []Finding{}
+
+	for _, newVersion := range newCRD.Spec.Versions {
+		existingVersion := GetVersionByName(existingCRD, newVersion.Name)
+		if existingVersion == nil {
+			continue
+		}
+
+		existingSchemas := getSchemasByPath(existingVersion)
+		newSchemas := getSchemasByPath(&newVersion)
+
+		for _, path := range sharedSchemaPaths(existingSchemas, newSchemas) {
+			existingSchema, newSchema := existingSchemas[path].schema, newSchemas[path].schema
+			simpleLocation := newSchemas[path].simpleLocation
+
+			if preservesUnknownFields(&existingSchema) && !preservesUnknownFields(&newSchema) {
+				msg := fmt.Sprintf("crd/%v version/%v field/%v x-kubernetes-preserve-unknown-fields may not be removed: fields stored under it that are not in the schema will be dropped on the next write", newCRD.Name, newVersion.Name, simpleLocation)
+				errsToReport = append(errsToReport, NewError(newCRD.Name, newVersion.Name, simpleLocation, msg).WithValues(strconv.FormatBool(true), strconv.FormatBool(false)).WithDiscriminator("x-kubernetes-preserve-unknown-fields"))
+			}
+			if existingSchema.Nullable && !newSchema.Nullable {
+				msg := fmt.Sprintf("crd/%v version/%v field/%v nullable may not be removed: objects that store null for it will be rejected on the next write", newCRD.Name, newVersion.Name, simpleLocation)
+				errsToReport = append(errsToReport, NewError(newCRD.Name, newVersion.Name, simpleLocation, msg).WithValues(strconv.FormatBool(true), strconv.FormatBool(false)).WithDiscriminator("nullable"))
+			}
+		}
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
+}
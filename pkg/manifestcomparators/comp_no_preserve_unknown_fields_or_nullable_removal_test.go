@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestNoPreserveUnknownFieldsOrNullableRemoval(t *testing.T) {
+	RunAllTestsInDirForComparator(t, NoPreserveUnknownFieldsOrNullableRemoval(), "testdata/no_preserve_unknown_fields_or_nullable_removal")
+}
@@ -0,0 +1,115 @@
+package manifestcomparators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+type noSSATopologyChange struct{}
+
+func NoSSATopologyChange() CRDComparator {
+	return noSSATopologyChange{}
+}
+
+func (noSSATopologyChange) Name() string {
+	return "NoSSATopologyChange"
+}
+
+func (noSSATopologyChange) WhyItMatters() string {
+	return "Server-side apply tracks field ownership by the x-kubernetes-list-type, x-kubernetes-list-map-keys, and " +
+		"x-kubernetes-map-type of lists and maps.  If they change, then the ownership recorded for every existing " +
+		"applier no longer matches and applies may remove or conflict on values owned by others."
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func describeMarkerChange(existingValue, newValue string) string {
+	switch {
+	case len(existingValue) == 0:
+		return fmt.Sprintf("added with value %v", newValue)
+	case len(newValue) == 0:
+		return fmt.Sprintf("removed, it was %v", existingValue)
+	default:
+		return fmt.Sprintf("changed from %v to %v", existingValue, newValue)
+	}
+}
+
+// describeDefaultMarkerChange describes adding or removing a marker with its default value.
+func describeDefaultMarkerChange(existingValue, newValue string) string {
+	if len(existingValue) == 0 {
+		return fmt.Sprintf("added with value %v, which matches the previous behavior", newValue)
+	}
+	return fmt.Sprintf("removed, it was %v, which is the default", existingValue)
+}
+
+// isDefaultMarkerChange is true when the marker is added or removed with its default value, which does not change
+// ownership.
+func isDefaultMarkerChange(existingValue, newValue, defaultValue string) bool {
+	return (len(existingValue) == 0 && newValue == defaultValue) || (existingValue == defaultValue && len(newValue) == 0)
+}
+
+func (b noSSATopologyChange) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	if existingCRD == nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
+	}
+	findings := []Finding{}
+
+	for _, newVersion := range newCRD.Spec.Versions {
+		existingVersion := GetVersionByName(existingCRD, newVersion.Name)
+		if existingVersion == nil {
+			continue
+		}
+
+		existingSchemas := getSchemasByPath(existingVersion)
+		newSchemas := getSchemasByPath(&newVersion)
+
+		for _, path := range sharedSchemaPaths(existingSchemas, newSchemas) {
+			existingSchema, newSchema := existingSchemas[path].schema, newSchemas[path].schema
+			simpleLocation := newSchemas[path].simpleLocation
+			prefix := fmt.Sprintf("crd/%v version/%v field/%v", newCRD.Name, newVersion.Name, simpleLocation)
+
+			existingListType, newListType := stringOrEmpty(existingSchema.XListType), stringOrEmpty(newSchema.XListType)
+			switch {
+			case existingListType == newListType:
+			case isDefaultMarkerChange(existingListType, newListType, "atomic"):
+				// lists without a list type are atomic, so ownership does not change.
+				findings = append(findings, NewInfo(newCRD.Name, newVersion.Name, simpleLocation,
+					fmt.Sprintf("%v x-kubernetes-list-type was %v", prefix, describeDefaultMarkerChange(existingListType, newListType))).WithValues(existingListType, newListType).WithDiscriminator("x-kubernetes-list-type"))
+			default:
+				findings = append(findings, NewError(newCRD.Name, newVersion.Name, simpleLocation,
+					fmt.Sprintf("%v x-kubernetes-list-type may not be %v", prefix, describeMarkerChange(existingListType, newListType))).WithValues(existingListType, newListType).WithDiscriminator("x-kubernetes-list-type"))
+			}
+
+			// the order of the keys does not change the identity of list items.
+			existingKeys, newKeys := append([]string{}, existingSchema.XListMapKeys...), append([]string{}, newSchema.XListMapKeys...)
+			sort.Strings(existingKeys)
+			sort.Strings(newKeys)
+			if existingKeysString, newKeysString := strings.Join(existingKeys, ", "), strings.Join(newKeys, ", "); existingKeysString != newKeysString {
+				findings = append(findings, NewError(newCRD.Name, newVersion.Name, simpleLocation,
+					fmt.Sprintf("%v x-kubernetes-list-map-keys may not be %v", prefix, describeMarkerChange(existingKeysString, newKeysString))).WithValues(existingKeysString, newKeysString).WithDiscriminator("x-kubernetes-list-map-keys"))
+			}
+
+			existingMapType, newMapType := stringOrEmpty(existingSchema.XMapType), stringOrEmpty(newSchema.XMapType)
+			switch {
+			case existingMapType == newMapType:
+			case isDefaultMarkerChange(existingMapType, newMapType, "granular"):
+				// maps without a map type are granular, so ownership does not change.
+				findings = append(findings, NewInfo(newCRD.Name, newVersion.Name, simpleLocation,
+					fmt.Sprintf("%v x-kubernetes-map-type was %v", prefix, describeDefaultMarkerChange(existingMapType, newMapType))).WithValues(existingMapType, newMapType).WithDiscriminator("x-kubernetes-map-type"))
+			default:
+				findings = append(findings, NewError(newCRD.Name, newVersion.Name, simpleLocation,
+					fmt.Sprintf("%v x-kubernetes-map-type may not be %v", prefix, describeMarkerChange(existingMapType, newMapType))).WithValues(existingMapType, newMapType).WithDiscriminator("x-kubernetes-map-type"))
+			}
+		}
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), findings), nil
+}
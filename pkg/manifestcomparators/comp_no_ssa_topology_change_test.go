@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestNoSSATopologyChange(t *testing.T) {
+	RunAllTestsInDirForComparator(t, NoSSATopologyChange(), "testdata/no_ssa_topology_change")
+}
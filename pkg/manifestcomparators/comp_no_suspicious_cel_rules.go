@@ -0,0 +1,94 @@
+package manifestcomparators
+
+import (
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type noSuspiciousCELRules struct {
+	kubeVersions KubeVersionRange
+}
+
+func NoSuspiciousCELRules() CRDComparator {
+	return NoSuspiciousCELRulesForKubeVersions(DefaultKubeVersionRange())
+}
+
+// NoSuspiciousCELRulesForKubeVersions type checks rules with the CEL libraries of the oldest version of kubeVersions.
+func NoSuspiciousCELRulesForKubeVersions(kubeVersions KubeVersionRange) CRDComparator {
+	return noSuspiciousCELRules{kubeVersions: kubeVersions}
+}
+
+func (noSuspiciousCELRules) Name() string {
+	return "NoSuspiciousCELRules"
+}
+
+func (noSuspiciousCELRules) WhyItMatters() string {
+	return "CEL rules that access optional fields without has(), check has() on required fields, or compare values " +
+		"that always compare the same compile and pass review, and then reject valid objects or never reject anything.  " +
+		"A messageExpression that fails replaces the helpful message with a generic one.  A rule or messageExpression " +
+		"that selects a field that is not declared, usually a misspelling, does not compile."
+}
+
+func (b noSuspiciousCELRules) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	findings := []Finding{}
+
+	for _, newVersion := range crd.Spec.Versions {
+		if newVersion.Schema == nil {
+			continue
+		}
+		SchemaHas(newVersion.Schema.OpenAPIV3Schema, field.NewPath("^"), field.NewPath("^"), nil,
+			func(s *apiextensionsv1.JSONSchemaProps, fldPath, simpleLocation *field.Path, ancestry []*apiextensionsv1.JSONSchemaProps) bool {
+				if len(s.XValidations) == 0 {
+					return false
+				}
+				// rules that fail to compile are reported by MustNotExceedCostBudget, only their undefined fields are named.
+				checkedRules, schemas, err := checkXValidations(b.kubeVersions, append(ancestry, s), fldPath)
+				if err != nil {
+					return false
+				}
+				prefix := fmt.Sprintf("crd/%v version/%v field/%v", crd.Name, newVersion.Name, simpleLocation)
+				for i, checked := range checkedRules {
+					rule := s.XValidations[i]
+					// a field that is not declared is most likely misspelled, which is easier to fix when it is named.
+					for _, fieldName := range checked.ruleUndefinedFields {
+						findings = append(findings, NewWarning(crd.Name, newVersion.Name, simpleLocation.String(),
+							fmt.Sprintf("%v rule %q does not compile, it selects %v, which is not declared in the schema", prefix, rule.Rule, fieldName)).WithDiscriminator(fmt.Sprintf("rule[%d] %v", i, fieldName)))
+					}
+					for _, fieldName := range checked.messageExpressionUndefinedFields {
+						findings = append(findings, NewWarning(crd.Name, newVersion.Name, simpleLocation.String(),
+							fmt.Sprintf("%v messageExpression %q does not compile, it selects %v, which is not declared in the schema", prefix, rule.MessageExpression, fieldName)).WithDiscriminator(fmt.Sprintf("rule[%d].messageExpression %v", i, fieldName)))
+					}
+					if checked.rule == nil {
+						continue
+					}
+
+					ruleIssues, ruleGuards := lintCELExpression(checked.rule, schemas, sets.NewString())
+					for _, issue := range ruleIssues {
+						findings = append(findings, NewWarning(crd.Name, newVersion.Name, simpleLocation.String(),
+							fmt.Sprintf("%v rule %q %v", prefix, rule.Rule, issue.message)).WithDiscriminator(fmt.Sprintf("rule[%d] %v", i, issue.subject)))
+					}
+
+					if checked.messageExpression == nil {
+						continue
+					}
+					// the messageExpression is only evaluated when the rule failed, so the fields the rule checked are set.
+					messageIssues, _ := lintCELExpression(checked.messageExpression, schemas, ruleGuards)
+					for _, issue := range messageIssues {
+						// the apiserver falls back to the message when the messageExpression fails.
+						findings = append(findings, NewWarning(crd.Name, newVersion.Name, simpleLocation.String(),
+							fmt.Sprintf("%v messageExpression %q can fail, it %v", prefix, rule.MessageExpression, issue.message)).WithDiscriminator(fmt.Sprintf("rule[%d].messageExpression %v", i, issue.subject)))
+					}
+				}
+				return false
+			})
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), findings), nil
+}
+
+func (b noSuspiciousCELRules) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	return RatchetCompare(b, existingCRD, newCRD)
+}
@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestNoSuspiciousCELRules(t *testing.T) {
+	RunAllTestsInDirForComparator(t, NoSuspiciousCELRules(), "testdata/no_suspicious_cel_rules")
+}
@@ -22,7 +22,7 @@ func (noUints) WhyItMatters() string {
 }
 
 func (n noUints) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
-	errsToReport := []string{}
+	errsToReport := []Finding{}
 
 	for _, newVersion := range crd.Spec.Versions {
 		uintFields := []string{}
@@ -35,19 +35,12 @@ func (n noUints) Validate(crd *apiextensionsv1.CustomResourceDefinition) (Compar
 			})
 
 		for _, newUintField := range uintFields {
-			errsToReport = append(errsToReport, fmt.Sprintf("crd/%v version/%v field/%v may not be a uint", crd.Name, newVersion.Name, newUintField))
+			errsToReport = append(errsToReport, NewError(crd.Name, newVersion.Name, newUintField, fmt.Sprintf("crd/%v version/%v field/%v may not be a uint", crd.Name, newVersion.Name, newUintField)))
 		}
 
 	}
 
-	return ComparisonResults{
-		Name:         n.Name(),
-		WhyItMatters: n.WhyItMatters(),
-
-		Errors:   errsToReport,
-		Warnings: nil,
-		Infos:    nil,
-	}, nil
+	return NewComparisonResults(n.Name(), n.WhyItMatters(), errsToReport), nil
 }
 
 func (n noUints) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
@@ -0,0 +1,127 @@
+package manifestcomparators
+
+import (
+	"fmt"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type noUnratchetedCELRules struct {
+	kubeVersions KubeVersionRange
+}
+
+func NoUnratchetedCELRules() CRDComparator {
+	return NoUnratchetedCELRulesForKubeVersions(DefaultKubeVersionRange())
+}
+
+// NoUnratchetedCELRulesForKubeVersions compiles added rules with the CEL libraries of the oldest version of
+// kubeVersions to tell transition rules apart.
+func NoUnratchetedCELRulesForKubeVersions(kubeVersions KubeVersionRange) CRDComparator {
+	return noUnratchetedCELRules{kubeVersions: kubeVersions}
+}
+
+func (noUnratchetedCELRules) Name() string {
+	return "NoUnratchetedCELRules"
+}
+
+func (noUnratchetedCELRules) WhyItMatters() string {
+	return "If a CEL rule is added to or changed on an existing field, then objects that are already stored may not " +
+		"satisfy it and clients will not be able to update them.  Transition rules and rules with optionalOldSelf only " +
+		"judge changes, so stored values keep validating.  Rules that make a field immutable break every controller " +
+		"that updates the field."
+}
+
+// isImmutabilityRule is true for rules that reject every change of the field.
+func isImmutabilityRule(rule string) bool {
+	rule = strings.Join(strings.Fields(rule), "")
+	return rule == "self==oldSelf" || rule == "oldSelf==self"
+}
+
+func (b noUnratchetedCELRules) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	if existingCRD == nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
+	}
+	findings := []Finding{}
+
+	for _, newVersion := range newCRD.Spec.Versions {
+		existingVersion := GetVersionByName(existingCRD, newVersion.Name)
+		if existingVersion == nil || newVersion.Schema == nil {
+			continue
+		}
+		existingSchemas := getSchemasByPath(existingVersion)
+
+		SchemaHas(newVersion.Schema.OpenAPIV3Schema, field.NewPath("^"), field.NewPath("^"), nil,
+			func(s *apiextensionsv1.JSONSchemaProps, fldPath, simpleLocation *field.Path, ancestry []*apiextensionsv1.JSONSchemaProps) bool {
+				existingSchema, ok := existingSchemas[fldPath.String()]
+				if !ok {
+					// rules on new fields cannot reject stored objects.
+					return false
+				}
+				prefix := fmt.Sprintf("crd/%v version/%v field/%v", newCRD.Name, newVersion.Name, simpleLocation)
+
+				existingRules, newRules := sets.NewString(), sets.NewString()
+				for _, rule := range existingSchema.schema.XValidations {
+					existingRules.Insert(rule.Rule)
+				}
+				for _, rule := range s.XValidations {
+					newRules.Insert(rule.Rule)
+				}
+
+				// rules are compared by their text, so reordering or inserting rules does not change the others.  A rule
+				// that replaces the only removed rule of the field is reported as changed.
+				addedRules, removedRules := newRules.Difference(existingRules), existingRules.Difference(newRules)
+				replaced := ""
+				if addedRules.Len() == 1 && removedRules.Len() == 1 {
+					replaced = removedRules.UnsortedList()[0]
+				}
+				for _, rule := range existingSchema.schema.XValidations {
+					if !removedRules.Has(rule.Rule) || rule.Rule == replaced {
+						continue
+					}
+					// a rule listed twice is reported once.
+					removedRules.Delete(rule.Rule)
+					findings = append(findings, NewInfo(newCRD.Name, newVersion.Name, simpleLocation.String(),
+						fmt.Sprintf("%v rule %q was removed", prefix, rule.Rule)).WithValues(rule.Rule, "").WithDiscriminator(rule.Rule))
+				}
+
+				if addedRules.Len() == 0 {
+					return false
+				}
+				compResults, _, err := compileXValidations(b.kubeVersions, append(ancestry, s), fldPath)
+				if err != nil {
+					// compilation errors are reported by MustNotExceedCostBudget.
+					compResults = nil
+				}
+
+				for i, rule := range s.XValidations {
+					if !addedRules.Has(rule.Rule) {
+						continue
+					}
+					addedRules.Delete(rule.Rule)
+					change := fmt.Sprintf("rule %q may not be added to an existing field", rule.Rule)
+					if len(replaced) > 0 {
+						change = fmt.Sprintf("rule %q may not be changed to %q", replaced, rule.Rule)
+					}
+
+					switch {
+					case isImmutabilityRule(rule.Rule):
+						findings = append(findings, NewError(newCRD.Name, newVersion.Name, simpleLocation.String(),
+							fmt.Sprintf("%v may not be made immutable with rule %q after it was created, controllers that update it will be rejected", prefix, rule.Rule)).WithValues(replaced, rule.Rule).WithDiscriminator(rule.Rule))
+					case rule.OptionalOldSelf != nil && *rule.OptionalOldSelf:
+					case i < len(compResults) && compResults[i].Error == nil && compResults[i].UsesOldSelf:
+						// transition rules are only evaluated on updates and judge the change, not the stored value.
+					default:
+						findings = append(findings, NewError(newCRD.Name, newVersion.Name, simpleLocation.String(),
+							fmt.Sprintf("%v %v unless it is a transition rule or sets optionalOldSelf, objects that are already stored may not satisfy it", prefix, change)).WithValues(replaced, rule.Rule).WithDiscriminator(rule.Rule))
+					}
+				}
+
+				return false
+			})
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), findings), nil
+}
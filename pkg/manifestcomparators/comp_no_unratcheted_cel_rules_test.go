@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestNoUnratchetedCELRules(t *testing.T) {
+	RunAllTestsInDirForComparator(t, NoUnratchetedCELRules(), "testdata/no_unratcheted_cel_rules")
+}
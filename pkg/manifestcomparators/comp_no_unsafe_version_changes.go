@@ -0,0 +1,74 @@
+package manifestcomparators
+
+import (
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type noUnsafeVersionChanges struct{}
+
+func NoUnsafeVersionChanges() CRDComparator {
+	return noUnsafeVersionChanges{}
+}
+
+func (noUnsafeVersionChanges) Name() string {
+	return "NoUnsafeVersionChanges"
+}
+
+func (noUnsafeVersionChanges) WhyItMatters() string {
+	return "If a served version stops being served without being deprecated first, then clients get no warning before " +
+		"their requests fail.  If a version that objects are stored in is removed, then the apiserver can no longer " +
+		"read those objects.  If the storage version changes, then existing objects stay stored in the old version " +
+		"until they are migrated."
+}
+
+func getStorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+func (b noUnsafeVersionChanges) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	if existingCRD == nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
+	}
+	findings := []Finding{}
+	storedVersions := sets.NewString(existingCRD.Status.StoredVersions...)
+
+	for _, existingVersion := range existingCRD.Spec.Versions {
+		newVersion := GetVersionByName(newCRD, existingVersion.Name)
+
+		if newVersion == nil {
+			if existingVersion.Served && !existingVersion.Deprecated {
+				msg := fmt.Sprintf("crd/%v version/%v may not be removed while it is served, it must be deprecated first", newCRD.Name, existingVersion.Name)
+				findings = append(findings, NewError(newCRD.Name, existingVersion.Name, "", msg).WithDiscriminator("served"))
+			}
+			if storedVersions.Has(existingVersion.Name) {
+				msg := fmt.Sprintf("crd/%v version/%v may not be removed while it is in status.storedVersions, migrate the stored objects and remove it from status.storedVersions first", newCRD.Name, existingVersion.Name)
+				findings = append(findings, NewError(newCRD.Name, existingVersion.Name, "", msg).WithDiscriminator("storedVersions"))
+			}
+			continue
+		}
+
+		if existingVersion.Served && !newVersion.Served && !existingVersion.Deprecated {
+			msg := fmt.Sprintf("crd/%v version/%v may not stop being served, it must be deprecated first", newCRD.Name, existingVersion.Name)
+			findings = append(findings, NewError(newCRD.Name, existingVersion.Name, "", msg).WithValues("served", "not served").WithDiscriminator("served"))
+		}
+	}
+
+	existingStorageVersion, newStorageVersion := getStorageVersion(existingCRD), getStorageVersion(newCRD)
+	// the existing CRD has no storage version when it is created through admission.
+	if len(existingStorageVersion) > 0 && len(newStorageVersion) > 0 && existingStorageVersion != newStorageVersion {
+		msg := fmt.Sprintf("crd/%v storage version changed from %v to %v: existing objects stay stored as %v until they are rewritten, "+
+			"so run a storage migration, for instance with kube-storage-version-migrator, and remove %v from status.storedVersions before %v is removed",
+			newCRD.Name, existingStorageVersion, newStorageVersion, existingStorageVersion, existingStorageVersion, existingStorageVersion)
+		findings = append(findings, NewWarning(newCRD.Name, newStorageVersion, "", msg).WithValues(existingStorageVersion, newStorageVersion).WithDiscriminator("storage"))
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), findings), nil
+}
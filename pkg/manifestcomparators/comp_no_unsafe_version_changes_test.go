@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestNoUnsafeVersionChanges(t *testing.T) {
+	RunAllTestsInDirForComparator(t, NoUnsafeVersionChanges(), "testdata/no_unsafe_version_changes")
+}
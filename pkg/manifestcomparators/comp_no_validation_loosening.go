@@ -0,0 +1,83 @@
+package manifestcomparators
+
+import (
+	"fmt"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type noValidationLoosening struct{}
+
+func NoValidationLoosening() CRDComparator {
+	return noValidationLoosening{}
+}
+
+func (noValidationLoosening) Name() string {
+	return "NoValidationLoosening"
+}
+
+func (noValidationLoosening) WhyItMatters() string {
+	return "If validation is loosened, then clients that relied on the old bounds, for instance to size a buffer or to " +
+		"handle every enum value, may fail on values that were impossible before."
+}
+
+func enumValues(s *apiextensionsv1.JSONSchemaProps) sets.String {
+	ret := sets.NewString()
+	for _, enum := range s.Enum {
+		ret.Insert(string(enum.Raw))
+	}
+	return ret
+}
+
+func (b noValidationLoosening) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	if existingCRD == nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
+	}
+	warnings := []Finding{}
+
+	for _, newVersion := range newCRD.Spec.Versions {
+		existingVersion := GetVersionByName(existingCRD, newVersion.Name)
+		if existingVersion == nil {
+			continue
+		}
+
+		existingSchemas := getSchemasByPath(existingVersion)
+		newSchemas := getSchemasByPath(&newVersion)
+
+		for _, path := range sharedSchemaPaths(existingSchemas, newSchemas) {
+			existingSchema, newSchema := existingSchemas[path].schema, newSchemas[path].schema
+			simpleLocation := newSchemas[path].simpleLocation
+			prefix := fmt.Sprintf("crd/%v version/%v field/%v", newCRD.Name, newVersion.Name, simpleLocation)
+			warn := func(validation, msg, oldValue, newValue string) {
+				warnings = append(warnings, NewWarning(newCRD.Name, newVersion.Name, simpleLocation, fmt.Sprintf("%v %v", prefix, msg)).WithValues(oldValue, newValue).WithDiscriminator(validation))
+			}
+
+			for _, change := range getBoundChanges(&existingSchema, &newSchema) {
+				if change.tightens {
+					continue
+				}
+				warn(change.name, fmt.Sprintf("%v was %v", change.name, change.description), change.oldValue, change.newValue)
+			}
+
+			// enum values added to an existing enum are reported by NoNewEnumValues.
+			if existingEnums := enumValues(&existingSchema); existingEnums.Len() > 0 && len(newSchema.Enum) == 0 {
+				warn("enum", fmt.Sprintf("enum was removed, it was %v", strings.Join(existingEnums.List(), ", ")), strings.Join(existingEnums.List(), ", "), "")
+			}
+
+			if len(existingSchema.Pattern) > 0 && existingSchema.Pattern != newSchema.Pattern {
+				// patterns that cannot be shown to be wider are reported by NoPatternOrFormatTightening.
+				if superset, _, err := patternAcceptsSuperset(existingSchema.Pattern, newSchema.Pattern); err == nil && superset {
+					warn("pattern", fmt.Sprintf("pattern %v", describePatternChange(existingSchema.Pattern, newSchema.Pattern)), existingSchema.Pattern, newSchema.Pattern)
+				}
+			}
+
+			if len(existingSchema.Format) > 0 && len(newSchema.Format) == 0 {
+				warn("format", fmt.Sprintf("format %v was removed", existingSchema.Format), existingSchema.Format, "")
+			}
+		}
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), warnings), nil
+}
@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestNoValidationLoosening(t *testing.T) {
+	RunAllTestsInDirForComparator(t, NoValidationLoosening(), "testdata/no_validation_loosening")
+}
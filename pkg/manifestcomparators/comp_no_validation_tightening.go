@@ -0,0 +1,193 @@
+package manifestcomparators
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+type noValidationTightening struct{}
+
+func NoValidationTightening() CRDComparator {
+	return noValidationTightening{}
+}
+
+func (noValidationTightening) Name() string {
+	return "NoValidationTightening"
+}
+
+func (noValidationTightening) WhyItMatters() string {
+	return "If validation is tightened, then objects that are already stored may no longer be valid and clients will not " +
+		"be able to update them without first changing values they did not intend to touch."
+}
+
+// schemaBound is a numeric validation of a schema.  Upper bounds accept more values as they grow, lower bounds accept
+// more values as they shrink.
+type schemaBound struct {
+	name  string
+	upper bool
+	// unset is the value that is equivalent to the bound not being set.
+	unset float64
+	// get returns the value of the bound and whether it is set.
+	get func(s *apiextensionsv1.JSONSchemaProps) (float64, bool)
+}
+
+func floatBound(value *float64) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	return *value, true
+}
+
+func intBound(value *int64) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	return float64(*value), true
+}
+
+var schemaBounds = []schemaBound{
+	{name: "maximum", upper: true, unset: math.Inf(1), get: func(s *apiextensionsv1.JSONSchemaProps) (float64, bool) { return floatBound(s.Maximum) }},
+	{name: "minimum", upper: false, unset: math.Inf(-1), get: func(s *apiextensionsv1.JSONSchemaProps) (float64, bool) { return floatBound(s.Minimum) }},
+	{name: "maxLength", upper: true, unset: math.Inf(1), get: func(s *apiextensionsv1.JSONSchemaProps) (float64, bool) { return intBound(s.MaxLength) }},
+	{name: "minLength", upper: false, unset: 0, get: func(s *apiextensionsv1.JSONSchemaProps) (float64, bool) { return intBound(s.MinLength) }},
+	{name: "maxItems", upper: true, unset: math.Inf(1), get: func(s *apiextensionsv1.JSONSchemaProps) (float64, bool) { return intBound(s.MaxItems) }},
+	{name: "minItems", upper: false, unset: 0, get: func(s *apiextensionsv1.JSONSchemaProps) (float64, bool) { return intBound(s.MinItems) }},
+	{name: "maxProperties", upper: true, unset: math.Inf(1), get: func(s *apiextensionsv1.JSONSchemaProps) (float64, bool) { return intBound(s.MaxProperties) }},
+	{name: "minProperties", upper: false, unset: 0, get: func(s *apiextensionsv1.JSONSchemaProps) (float64, bool) { return intBound(s.MinProperties) }},
+}
+
+// schemaFlag is a boolean validation of a schema that rejects more values when it is turned on.
+type schemaFlag struct {
+	name string
+	get  func(s *apiextensionsv1.JSONSchemaProps) bool
+	// appliesTo is false for schemas the flag has no effect on, like exclusiveMaximum without a maximum.  nil when the
+	// flag always applies.
+	appliesTo func(s *apiextensionsv1.JSONSchemaProps) bool
+}
+
+var schemaFlags = []schemaFlag{
+	{name: "exclusiveMaximum", get: func(s *apiextensionsv1.JSONSchemaProps) bool { return s.ExclusiveMaximum }, appliesTo: func(s *apiextensionsv1.JSONSchemaProps) bool { return s.Maximum != nil }},
+	{name: "exclusiveMinimum", get: func(s *apiextensionsv1.JSONSchemaProps) bool { return s.ExclusiveMinimum }, appliesTo: func(s *apiextensionsv1.JSONSchemaProps) bool { return s.Minimum != nil }},
+	{name: "uniqueItems", get: func(s *apiextensionsv1.JSONSchemaProps) bool { return s.UniqueItems }},
+}
+
+// boundChange describes how a single bound or flag changed between two schemas.
+type boundChange struct {
+	name     string
+	tightens bool
+	// description is how the validation changed, for instance "decreased from 10 to 5".
+	description        string
+	oldValue, newValue string
+}
+
+func formatBound(value float64, set bool) string {
+	if !set {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// getBoundChanges returns every change of a numeric bound or flag between the existing and new schema, in the order
+// of schemaBounds and schemaFlags.
+func getBoundChanges(existingSchema, newSchema *apiextensionsv1.JSONSchemaProps) []boundChange {
+	changes := []boundChange{}
+	for _, bound := range schemaBounds {
+		existingValue, existingSet := bound.get(existingSchema)
+		newValue, newSet := bound.get(newSchema)
+		if existingSet == newSet && existingValue == newValue {
+			continue
+		}
+
+		effectiveExistingValue, effectiveNewValue := existingValue, newValue
+		if !existingSet {
+			effectiveExistingValue = bound.unset
+		}
+		if !newSet {
+			effectiveNewValue = bound.unset
+		}
+		if effectiveExistingValue == effectiveNewValue {
+			// for instance minLength: 0 added, which accepts the same values.
+			continue
+		}
+
+		change := boundChange{
+			name:     bound.name,
+			oldValue: formatBound(existingValue, existingSet),
+			newValue: formatBound(newValue, newSet),
+		}
+		if bound.upper {
+			change.tightens = effectiveNewValue < effectiveExistingValue
+		} else {
+			change.tightens = effectiveNewValue > effectiveExistingValue
+		}
+		switch {
+		case !existingSet:
+			change.description = fmt.Sprintf("added with value %v", change.newValue)
+		case !newSet:
+			change.description = fmt.Sprintf("removed, it was %v", change.oldValue)
+		case effectiveNewValue < effectiveExistingValue:
+			change.description = fmt.Sprintf("decreased from %v to %v", change.oldValue, change.newValue)
+		default:
+			change.description = fmt.Sprintf("increased from %v to %v", change.oldValue, change.newValue)
+		}
+		changes = append(changes, change)
+	}
+
+	for _, flag := range schemaFlags {
+		existingValue, newValue := flag.get(existingSchema), flag.get(newSchema)
+		if existingValue == newValue {
+			continue
+		}
+		if flag.appliesTo != nil && !flag.appliesTo(newSchema) {
+			// the change of the bound itself is reported, the flag does not change which values are accepted.
+			continue
+		}
+		change := boundChange{
+			name:     flag.name,
+			tightens: newValue,
+			oldValue: strconv.FormatBool(existingValue),
+			newValue: strconv.FormatBool(newValue),
+		}
+		if newValue {
+			change.description = "enabled"
+		} else {
+			change.description = "disabled"
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+func (b noValidationTightening) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	if existingCRD == nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), nil), nil
+	}
+	errsToReport := []Finding{}
+
+	for _, newVersion := range newCRD.Spec.Versions {
+		existingVersion := GetVersionByName(existingCRD, newVersion.Name)
+		if existingVersion == nil {
+			continue
+		}
+
+		existingSchemas := getSchemasByPath(existingVersion)
+		newSchemas := getSchemasByPath(&newVersion)
+
+		for _, path := range sharedSchemaPaths(existingSchemas, newSchemas) {
+			existingSchema, newSchema := existingSchemas[path], newSchemas[path]
+			for _, change := range getBoundChanges(&existingSchema.schema, &newSchema.schema) {
+				if !change.tightens {
+					continue
+				}
+				msg := fmt.Sprintf("crd/%v version/%v field/%v %v may not be %v", newCRD.Name, newVersion.Name, newSchema.simpleLocation, change.name, change.description)
+				errsToReport = append(errsToReport, NewError(newCRD.Name, newVersion.Name, newSchema.simpleLocation, msg).WithValues(change.oldValue, change.newValue).WithDiscriminator(change.name))
+			}
+		}
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
+}
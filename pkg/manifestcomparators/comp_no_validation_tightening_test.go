@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestNoValidationTightening(t *testing.T) {
+	RunAllTestsInDirForComparator(t, NoValidationTightening(), "testdata/no_validation_tightening")
+}
@@ -0,0 +1,187 @@
+package manifestcomparators
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+type schemasMustMatchWithoutConversion struct{}
+
+func SchemasMustMatchWithoutConversion() CRDComparator {
+	return schemasMustMatchWithoutConversion{}
+}
+
+func (schemasMustMatchWithoutConversion) Name() string {
+	return "SchemasMustMatchWithoutConversion"
+}
+
+func (schemasMustMatchWithoutConversion) WhyItMatters() string {
+	return "With conversion strategy None the apiserver only rewrites the apiVersion, so every served version must have " +
+		"the same schema as the storage version.  Otherwise data is pruned or fails validation when it is written or " +
+		"read through another version."
+}
+
+// schemaAspect is a part of a single schema node that must be identical across versions.  value returns "" when the
+// aspect is not set.
+type schemaAspect struct {
+	name  string
+	value func(s *apiextensionsv1.JSONSchemaProps) string
+}
+
+func jsonOrEmpty(value interface{}) string {
+	ret, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(ret)
+}
+
+var schemaAspects = func() []schemaAspect {
+	ret := []schemaAspect{
+		{name: "type", value: func(s *apiextensionsv1.JSONSchemaProps) string { return s.Type }},
+		{name: "format", value: func(s *apiextensionsv1.JSONSchemaProps) string { return s.Format }},
+		{name: "pattern", value: func(s *apiextensionsv1.JSONSchemaProps) string { return s.Pattern }},
+		{name: "enum", value: func(s *apiextensionsv1.JSONSchemaProps) string { return strings.Join(enumValues(s).List(), ", ") }},
+		{name: "required", value: func(s *apiextensionsv1.JSONSchemaProps) string {
+			required := append([]string{}, s.Required...)
+			sort.Strings(required)
+			return strings.Join(required, ", ")
+		}},
+		{name: "nullable", value: func(s *apiextensionsv1.JSONSchemaProps) string {
+			if !s.Nullable {
+				return ""
+			}
+			return strconv.FormatBool(s.Nullable)
+		}},
+		{name: "x-kubernetes-preserve-unknown-fields", value: func(s *apiextensionsv1.JSONSchemaProps) string {
+			if !preservesUnknownFields(s) {
+				return ""
+			}
+			return strconv.FormatBool(true)
+		}},
+		{name: "default", value: func(s *apiextensionsv1.JSONSchemaProps) string {
+			_, canonical, _, err := defaultValue(s)
+			if err != nil {
+				return string(s.Default.Raw)
+			}
+			return canonical
+		}},
+		{name: "x-kubernetes-validations", value: func(s *apiextensionsv1.JSONSchemaProps) string {
+			if len(s.XValidations) == 0 {
+				return ""
+			}
+			return jsonOrEmpty(s.XValidations)
+		}},
+	}
+	for _, bound := range schemaBounds {
+		bound := bound
+		ret = append(ret, schemaAspect{name: bound.name, value: func(s *apiextensionsv1.JSONSchemaProps) string { return formatBound(bound.get(s)) }})
+	}
+	for _, flag := range schemaFlags {
+		flag := flag
+		ret = append(ret, schemaAspect{name: flag.name, value: func(s *apiextensionsv1.JSONSchemaProps) string {
+			if !flag.get(s) {
+				return ""
+			}
+			return strconv.FormatBool(true)
+		}})
+	}
+	return ret
+}()
+
+func describeAspect(value string) string {
+	if len(value) == 0 {
+		return "unset"
+	}
+	return value
+}
+
+// isChildPath is true when path is below parent, for instance ^.properties[spec].properties[foo] is below
+// ^.properties[spec].
+func isChildPath(path, parent string) bool {
+	return len(path) > len(parent) && strings.HasPrefix(path, parent) && strings.ContainsRune(".[", rune(path[len(parent)]))
+}
+
+func (b schemasMustMatchWithoutConversion) Validate(crd *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	errsToReport := []Finding{}
+	if crd.Spec.Conversion != nil && crd.Spec.Conversion.Strategy != apiextensionsv1.NoneConverter {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
+	}
+
+	// every served version is compared with the storage version, which is what the data is read from and written to.
+	var storageVersion *apiextensionsv1.CustomResourceDefinitionVersion
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Storage {
+			storageVersion = &crd.Spec.Versions[i]
+		}
+	}
+	if storageVersion == nil {
+		return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
+	}
+	storageSchemas := getSchemasByPath(storageVersion)
+
+	for _, version := range crd.Spec.Versions {
+		if version.Name == storageVersion.Name || !version.Served {
+			continue
+		}
+		versionSchemas := getSchemasByPath(&version)
+
+		allPaths := map[string]bool{}
+		for path := range storageSchemas {
+			allPaths[path] = true
+		}
+		for path := range versionSchemas {
+			allPaths[path] = true
+		}
+		paths := []string{}
+		for path := range allPaths {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		// only the topmost field of a missing subtree is reported.
+		missingPath := ""
+		for _, path := range paths {
+			if len(missingPath) > 0 && isChildPath(path, missingPath) {
+				continue
+			}
+			storageSchema, inStorage := storageSchemas[path]
+			versionSchema, inVersion := versionSchemas[path]
+			switch {
+			case !inStorage:
+				missingPath = path
+				msg := fmt.Sprintf("crd/%v version/%v field/%v does not exist in storage version/%v and is pruned with conversion strategy None",
+					crd.Name, version.Name, versionSchema.simpleLocation, storageVersion.Name)
+				errsToReport = append(errsToReport, NewError(crd.Name, version.Name, versionSchema.simpleLocation, msg))
+				continue
+			case !inVersion:
+				missingPath = path
+				msg := fmt.Sprintf("crd/%v version/%v field/%v only exists in storage version/%v and is pruned with conversion strategy None",
+					crd.Name, version.Name, storageSchema.simpleLocation, storageVersion.Name)
+				errsToReport = append(errsToReport, NewError(crd.Name, version.Name, storageSchema.simpleLocation, msg))
+				continue
+			}
+
+			for _, aspect := range schemaAspects {
+				storageValue, versionValue := aspect.value(&storageSchema.schema), aspect.value(&versionSchema.schema)
+				if storageValue == versionValue {
+					continue
+				}
+				msg := fmt.Sprintf("crd/%v version/%v field/%v %v is %v but it is %v in storage version/%v, which conversion strategy None cannot convert",
+					crd.Name, version.Name, versionSchema.simpleLocation, aspect.name, describeAspect(versionValue), describeAspect(storageValue), storageVersion.Name)
+				errsToReport = append(errsToReport, NewError(crd.Name, version.Name, versionSchema.simpleLocation, msg).WithValues(storageValue, versionValue).WithDiscriminator(aspect.name))
+			}
+		}
+	}
+
+	return NewComparisonResults(b.Name(), b.WhyItMatters(), errsToReport), nil
+}
+
+func (b schemasMustMatchWithoutConversion) Compare(existingCRD, newCRD *apiextensionsv1.CustomResourceDefinition) (ComparisonResults, error) {
+	return RatchetCompare(b, existingCRD, newCRD)
+}
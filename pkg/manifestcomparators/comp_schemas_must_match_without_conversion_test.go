@@ -0,0 +1,7 @@
+package manifestcomparators
+
+import "testing"
+
+func TestSchemasMustMatchWithoutConversion(t *testing.T) {
+	RunAllTestsInDirForComparator(t, SchemasMustMatchWithoutConversion(), "testdata/schemas_must_match_without_conversion")
+}
@@ -0,0 +1,101 @@
+package manifestcomparators
+
+// Severity indicates how a Finding should be treated by consumers.
+type Severity string
+
+const (
+	SeverityError   Severity = "Error"
+	SeverityWarning Severity = "Warning"
+	SeverityInfo    Severity = "Info"
+)
+
+// Finding is a single, structured result produced by a comparator.  Consumers should prefer filtering and grouping
+// on the structured fields instead of parsing Message.
+type Finding struct {
+	// Comparator is the name of the comparator that produced the finding.
+	Comparator string   `json:"comparator" yaml:"comparator"`
+	Severity   Severity `json:"severity" yaml:"severity"`
+
+	CRDName string `json:"crdName" yaml:"crdName"`
+	// Version is the name of the CRD version the finding applies to.  Empty when the finding is not version specific.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// Field is the simple location of the field, for instance ^.spec.foo.  Empty when the finding is not field specific.
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
+
+	// Discriminator tells apart findings of one comparator on the same field, like the validation that changed or the
+	// index of a CEL rule.  Unlike the message it is stable, so it identifies findings together with the field.
+	Discriminator string `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
+
+	// Message is the complete human readable description of the finding.
+	Message string `json:"message" yaml:"message"`
+
+	// OldValue and NewValue are optional renderings of the value before and after the change.
+	OldValue string `json:"oldValue,omitempty" yaml:"oldValue,omitempty"`
+	NewValue string `json:"newValue,omitempty" yaml:"newValue,omitempty"`
+}
+
+func (f Finding) String() string {
+	return f.Message
+}
+
+// WithValues returns a copy of the finding with the old and new values set.
+func (f Finding) WithValues(oldValue, newValue string) Finding {
+	f.OldValue = oldValue
+	f.NewValue = newValue
+	return f
+}
+
+// WithDiscriminator returns a copy of the finding with the discriminator set.
+func (f Finding) WithDiscriminator(discriminator string) Finding {
+	f.Discriminator = discriminator
+	return f
+}
+
+func NewError(crdName, version, field, message string) Finding {
+	return newFinding(SeverityError, crdName, version, field, message)
+}
+
+func NewWarning(crdName, version, field, message string) Finding {
+	return newFinding(SeverityWarning, crdName, version, field, message)
+}
+
+func NewInfo(crdName, version, field, message string) Finding {
+	return newFinding(SeverityInfo, crdName, version, field, message)
+}
+
+func newFinding(severity Severity, crdName, version, field, message string) Finding {
+	return Finding{
+		Severity: severity,
+		CRDName:  crdName,
+		Version:  version,
+		Field:    field,
+		Message:  message,
+	}
+}
+
+// NewComparisonResults builds the results for a comparator from its findings.  Every finding is attributed to the
+// comparator and the Errors, Warnings, and Infos are rendered from the findings.
+func NewComparisonResults(name, whyItMatters string, findings []Finding) ComparisonResults {
+	ret := ComparisonResults{
+		Name:         name,
+		WhyItMatters: whyItMatters,
+		Errors:       []string{},
+		Warnings:     []string{},
+		Infos:        []string{},
+	}
+	for _, finding := range findings {
+		finding.Comparator = name
+		ret.Findings = append(ret.Findings, finding)
+
+		switch finding.Severity {
+		case SeverityError:
+			ret.Errors = append(ret.Errors, finding.String())
+		case SeverityWarning:
+			ret.Warnings = append(ret.Warnings, finding.String())
+		default:
+			ret.Infos = append(ret.Infos, finding.String())
+		}
+	}
+
+	return ret
+}
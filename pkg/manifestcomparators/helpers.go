@@ -1,6 +1,7 @@
 package manifestcomparators
 
 import (
+	"sort"
 	"sync"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -119,3 +120,39 @@ func schemaHasRecurse(s *apiextensionsv1.JSONSchemaProps, fldPath, simpleLocatio
 	*schema = *s
 	return SchemaHas(schema, fldPath, simpleLocation, ancestry, pred)
 }
+
+// locatedSchema is a copy of a schema node together with its simple location.
+type locatedSchema struct {
+	simpleLocation string
+	schema         apiextensionsv1.JSONSchemaProps
+}
+
+// getSchemasByPath returns a shallow copy of every schema node of the version, keyed by its full path.  Keying by the
+// full path instead of the simple location keeps nodes in allOf, anyOf, and oneOf apart, so they are only compared
+// with their counterparts.
+func getSchemasByPath(version *apiextensionsv1.CustomResourceDefinitionVersion) map[string]locatedSchema {
+	schemas := map[string]locatedSchema{}
+	if version == nil || version.Schema == nil {
+		return schemas
+	}
+	SchemaHas(version.Schema.OpenAPIV3Schema, field.NewPath("^"), field.NewPath("^"), nil,
+		func(s *apiextensionsv1.JSONSchemaProps, fldPath, simpleLocation *field.Path, _ []*apiextensionsv1.JSONSchemaProps) bool {
+			schemas[fldPath.String()] = locatedSchema{simpleLocation: simpleLocation.String(), schema: *s}
+			return false
+		})
+
+	return schemas
+}
+
+// sharedSchemaPaths returns the sorted paths of getSchemasByPath that exist in both existing and new, the nodes that
+// can be compared for changes.
+func sharedSchemaPaths(existing, new map[string]locatedSchema) []string {
+	paths := []string{}
+	for path := range new {
+		if _, ok := existing[path]; ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -3,12 +3,15 @@ package manifestcomparators
 import apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 type ComparisonResults struct {
-	Name         string `yaml:"name"`
-	WhyItMatters string `yaml:"whyItMatters"`
+	Name         string `json:"name" yaml:"name"`
+	WhyItMatters string `json:"whyItMatters" yaml:"whyItMatters"`
 
-	Errors   []string `yaml:"errors"`
-	Warnings []string `yaml:"warnings"`
-	Infos    []string `yaml:"infos"`
+	// Errors, Warnings, and Infos are the rendered messages of the Findings of the matching severity.
+	Errors   []string `json:"errors" yaml:"errors"`
+	Warnings []string `json:"warnings" yaml:"warnings"`
+	Infos    []string `json:"infos" yaml:"infos"`
+
+	Findings []Finding `json:"findings,omitempty" yaml:"findings,omitempty"`
 }
 
 type CRDComparator interface {
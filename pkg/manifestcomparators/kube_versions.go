@@ -0,0 +1,70 @@
+package manifestcomparators
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apiserver/pkg/cel/environment"
+	"k8s.io/apiserver/pkg/util/compatibility"
+)
+
+// minimumCELKubeVersion is the first Kubernetes version that enabled x-kubernetes-validations by default.
+var minimumCELKubeVersion = version.MajorMinor(1, 25)
+
+// KubeVersionRange is the range of Kubernetes versions that serve a CRD.  CEL rules must compile with the libraries of
+// the Oldest version.  The cost limits have not changed since x-kubernetes-validations were enabled by default, so
+// every version in the range applies the same ones.
+type KubeVersionRange struct {
+	Oldest *version.Version
+	Newest *version.Version
+}
+
+// DefaultKubeVersionRange is the CEL compatibility version of the vendored Kubernetes libraries.  Rules that compile
+// with it are portable to the versions that an apiserver of the vendored version may be rolled back to.
+func DefaultKubeVersionRange() KubeVersionRange {
+	compatibilityVersion := environment.DefaultCompatibilityVersion()
+	return KubeVersionRange{Oldest: compatibilityVersion, Newest: compatibilityVersion}
+}
+
+// ParseKubeVersionRange parses major.minor versions and oldest-newest ranges into the smallest range that holds all of
+// them.
+func ParseKubeVersionRange(values []string) (KubeVersionRange, error) {
+	ret := KubeVersionRange{}
+	newestSupported := compatibility.DefaultBuildEffectiveVersion().BinaryVersion()
+	newestSupported = version.MajorMinor(newestSupported.Major(), newestSupported.Minor())
+
+	for _, value := range values {
+		for _, versionString := range strings.Split(value, "-") {
+			kubeVersion, err := version.ParseGeneric(strings.TrimSpace(versionString))
+			if err != nil {
+				return KubeVersionRange{}, fmt.Errorf("kubernetes version %q must be major.minor or a range of oldest-newest: %w", value, err)
+			}
+			kubeVersion = version.MajorMinor(kubeVersion.Major(), kubeVersion.Minor())
+			if kubeVersion.LessThan(minimumCELKubeVersion) {
+				return KubeVersionRange{}, fmt.Errorf("kubernetes version %v is older than %v, the first version with CEL validation rules", kubeVersion, minimumCELKubeVersion)
+			}
+			if newestSupported.LessThan(kubeVersion) {
+				return KubeVersionRange{}, fmt.Errorf("kubernetes version %v is newer than %v, the newest version of the vendored libraries", kubeVersion, newestSupported)
+			}
+
+			if ret.Oldest == nil || kubeVersion.LessThan(ret.Oldest) {
+				ret.Oldest = kubeVersion
+			}
+			if ret.Newest == nil || ret.Newest.LessThan(kubeVersion) {
+				ret.Newest = kubeVersion
+			}
+		}
+	}
+	if ret.Oldest == nil {
+		return KubeVersionRange{}, fmt.Errorf("at least one kubernetes version is required")
+	}
+	return ret, nil
+}
+
+func (r KubeVersionRange) String() string {
+	if r.Oldest.EqualTo(r.Newest) {
+		return r.Oldest.String()
+	}
+	return fmt.Sprintf("%v-%v", r.Oldest, r.Newest)
+}
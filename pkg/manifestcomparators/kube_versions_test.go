@@ -0,0 +1,184 @@
+package manifestcomparators
+
+import (
+	"strings"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestParseKubeVersionRange(t *testing.T) {
+	tests := []struct {
+		name           string
+		values         []string
+		expectedOldest string
+		expectedNewest string
+		expectErr      bool
+	}{
+		{name: "single", values: []string{"1.30"}, expectedOldest: "1.30", expectedNewest: "1.30"},
+		{name: "patch is ignored", values: []string{"v1.30.4"}, expectedOldest: "1.30", expectedNewest: "1.30"},
+		{name: "range", values: []string{"1.28-1.31"}, expectedOldest: "1.28", expectedNewest: "1.31"},
+		{name: "list", values: []string{"1.31", "1.28", "1.29"}, expectedOldest: "1.28", expectedNewest: "1.31"},
+		{name: "before CEL", values: []string{"1.24"}, expectErr: true},
+		{name: "newer than vendored", values: []string{"1.999"}, expectErr: true},
+		{name: "not a version", values: []string{"latest"}, expectErr: true},
+		{name: "empty", values: []string{}, expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseKubeVersionRange(test.values)
+			switch {
+			case test.expectErr && err == nil:
+				t.Fatalf("expected error, got %v", actual)
+			case !test.expectErr && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case test.expectErr:
+				return
+			}
+			if actual.Oldest.String() != test.expectedOldest || actual.Newest.String() != test.expectedNewest {
+				t.Fatalf("expected %v-%v, got %v", test.expectedOldest, test.expectedNewest, actual)
+			}
+		})
+	}
+}
+
+func TestMustNotExceedCostBudgetForKubeVersions(t *testing.T) {
+	maxLength := int64(63)
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "schedulers.config.openshift.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name: "v1",
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"spec": {
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"release": {Type: "string", MaxLength: &maxLength},
+								},
+								XValidations: apiextensionsv1.ValidationRules{
+									// isSemver was added in 1.33.
+									{Rule: "!has(self.release) || isSemver(self.release)"},
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		kubeVersions  KubeVersionRange
+		expectedError string
+	}{
+		{
+			name:         "supported by every version",
+			kubeVersions: KubeVersionRange{Oldest: version.MajorMinor(1, 33), Newest: version.MajorMinor(1, 34)},
+		},
+		{
+			name:          "unavailable on the oldest version",
+			kubeVersions:  KubeVersionRange{Oldest: version.MajorMinor(1, 30), Newest: version.MajorMinor(1, 34)},
+			expectedError: "compiles with the libraries of Kubernetes 1.34 but not with those of Kubernetes 1.30, the oldest target",
+		},
+		{
+			name:          "unavailable on the only version",
+			kubeVersions:  KubeVersionRange{Oldest: version.MajorMinor(1, 30), Newest: version.MajorMinor(1, 30)},
+			expectedError: "compilation failed",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results, err := MustNotExceedCostBudgetForKubeVersions(test.kubeVersions).(SingleCRDValidator).Validate(crd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(test.expectedError) == 0 {
+				if len(results.Errors) != 0 {
+					t.Fatalf("unexpected errors: %v", results.Errors)
+				}
+				return
+			}
+			if len(results.Errors) != 1 || !strings.Contains(results.Errors[0], test.expectedError) {
+				t.Fatalf("expected one error containing %q, got %v", test.expectedError, results.Errors)
+			}
+		})
+	}
+}
+
+func TestCELComparatorsForKubeVersions(t *testing.T) {
+	maxLength := int64(63)
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "schedulers.config.openshift.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name: "v1",
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"spec": {
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"release": {
+										Type:      "string",
+										MaxLength: &maxLength,
+										Default:   &apiextensionsv1.JSON{Raw: []byte(`"latest"`)},
+										// isSemver was added in 1.33.
+										XValidations: apiextensionsv1.ValidationRules{{Rule: "isSemver(self)"}},
+									},
+									"minimumRelease": {Type: "string", MaxLength: &maxLength},
+								},
+								XValidations: apiextensionsv1.ValidationRules{
+									{Rule: "isSemver(self.minimumRelease)"},
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		kubeVersions     KubeVersionRange
+		expectedFindings int
+	}{
+		{
+			name:             "rules compile with the oldest version",
+			kubeVersions:     KubeVersionRange{Oldest: version.MajorMinor(1, 33), Newest: version.MajorMinor(1, 34)},
+			expectedFindings: 1,
+		},
+		{
+			name:         "rules do not compile with the oldest version",
+			kubeVersions: KubeVersionRange{Oldest: version.MajorMinor(1, 30), Newest: version.MajorMinor(1, 34)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			suspiciousResults, err := NoSuspiciousCELRulesForKubeVersions(test.kubeVersions).(SingleCRDValidator).Validate(crd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the unguarded access of minimumRelease is only linted when its rule compiles.
+			if len(suspiciousResults.Warnings) != test.expectedFindings {
+				t.Errorf("expected %d warnings, got %v", test.expectedFindings, suspiciousResults.Warnings)
+			}
+
+			defaultsResults, err := DefaultsMustBeValidForKubeVersions(test.kubeVersions).(SingleCRDValidator).Validate(crd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the default of release is not a semantic version, which is only checked when its rule compiles.
+			if len(defaultsResults.Errors) != test.expectedFindings {
+				t.Errorf("expected %d errors, got %v", test.expectedFindings, defaultsResults.Errors)
+			}
+		})
+	}
+}
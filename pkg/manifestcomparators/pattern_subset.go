@@ -0,0 +1,265 @@
+package manifestcomparators
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxPatternStates bounds the number of state pairs explored before patterns are considered too complex to analyze.
+const maxPatternStates = 10000
+
+// patternAcceptsSuperset reports whether every string matched by oldPattern is also matched by newPattern.  Patterns
+// match like the apiserver validates them: unanchored, so a match anywhere in the value is enough.  An empty pattern
+// matches everything.  When newPattern does not accept a superset, counterexample is a value that only oldPattern
+// accepts.  err is set when either pattern cannot be analyzed.
+func patternAcceptsSuperset(oldPattern, newPattern string) (bool, string, error) {
+	oldAutomaton, err := newPatternAutomaton(oldPattern)
+	if err != nil {
+		return false, "", err
+	}
+	newAutomaton, err := newPatternAutomaton(newPattern)
+	if err != nil {
+		return false, "", err
+	}
+	alphabet, err := patternAlphabet(oldAutomaton, newAutomaton)
+	if err != nil {
+		return false, "", err
+	}
+
+	type statePair struct {
+		oldState, newState patternState
+		value              string
+	}
+	start := statePair{oldState: oldAutomaton.start(), newState: newAutomaton.start()}
+	visited := map[string]bool{start.oldState.key() + "|" + start.newState.key(): true}
+	queue := []statePair{start}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+
+		if oldAutomaton.acceptsAtEnd(curr.oldState) && !newAutomaton.acceptsAtEnd(curr.newState) {
+			return false, curr.value, nil
+		}
+		if curr.newState.matched || oldAutomaton.dead(curr.oldState) {
+			// every continuation is accepted by the new pattern or rejected by the old pattern.
+			continue
+		}
+
+		for _, r := range alphabet {
+			next := statePair{
+				oldState: oldAutomaton.step(curr.oldState, r),
+				newState: newAutomaton.step(curr.newState, r),
+				value:    curr.value + string(r),
+			}
+			key := next.oldState.key() + "|" + next.newState.key()
+			if visited[key] {
+				continue
+			}
+			if len(visited) >= maxPatternStates {
+				return false, "", fmt.Errorf("patterns are too complex to compare")
+			}
+			visited[key] = true
+			queue = append(queue, next)
+		}
+	}
+
+	return true, "", nil
+}
+
+// patternAutomaton simulates the compiled program of a pattern one rune at a time.
+type patternAutomaton struct {
+	prog *syntax.Prog
+}
+
+// patternState is a set of program counters.  matched is sticky: once the pattern matched a substring, every
+// continuation of the value matches too.
+type patternState struct {
+	pcs     []int
+	matched bool
+	initial bool
+}
+
+func (s patternState) key() string {
+	if s.matched {
+		return "matched"
+	}
+	return fmt.Sprintf("%v%v", s.initial, s.pcs)
+}
+
+func newPatternAutomaton(pattern string) (*patternAutomaton, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse pattern %v: %w", pattern, err)
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile pattern %v: %w", pattern, err)
+	}
+	for _, inst := range prog.Inst {
+		if inst.Op == syntax.InstEmptyWidth && syntax.EmptyOp(inst.Arg)&^(syntax.EmptyBeginText|syntax.EmptyEndText) != 0 {
+			return nil, fmt.Errorf("pattern %v uses assertions other than ^ and $", pattern)
+		}
+	}
+	return &patternAutomaton{prog: prog}, nil
+}
+
+func (a *patternAutomaton) start() patternState {
+	state := a.closure([]int{a.prog.Start}, true, false)
+	state.initial = true
+	return state
+}
+
+func (a *patternAutomaton) step(s patternState, r rune) patternState {
+	if s.matched {
+		return s
+	}
+	next := []int{}
+	for _, pc := range s.pcs {
+		inst := &a.prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstRune, syntax.InstRune1, syntax.InstRuneAny, syntax.InstRuneAnyNotNL:
+			if inst.MatchRune(r) {
+				next = append(next, int(inst.Out))
+			}
+		}
+	}
+	// the pattern is unanchored, so a match can start at every position.
+	next = append(next, a.prog.Start)
+	return a.closure(next, false, false)
+}
+
+// dead is true when no continuation of the value can be matched, which happens after a ^ failed.
+func (a *patternAutomaton) dead(s patternState) bool {
+	return !s.matched && !s.initial && len(s.pcs) == 0
+}
+
+func (a *patternAutomaton) acceptsAtEnd(s patternState) bool {
+	if s.matched {
+		return true
+	}
+	// only $ is left to check, the runes are consumed.
+	for _, pc := range s.pcs {
+		if a.prog.Inst[pc].Op != syntax.InstEmptyWidth {
+			continue
+		}
+		if a.closure([]int{pc}, s.initial, true).matched {
+			return true
+		}
+	}
+	return false
+}
+
+// closure follows every instruction that does not consume a rune.  Instructions that wait for a rune, and $ when the
+// end of the value is not reached yet, are kept in the state.
+func (a *patternAutomaton) closure(starts []int, atStart, atEnd bool) patternState {
+	ret := patternState{}
+	visited := map[int]bool{}
+	stack := append([]int{}, starts...)
+	for len(stack) > 0 {
+		pc := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[pc] {
+			continue
+		}
+		visited[pc] = true
+
+		inst := &a.prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			stack = append(stack, int(inst.Out), int(inst.Arg))
+		case syntax.InstCapture, syntax.InstNop:
+			stack = append(stack, int(inst.Out))
+		case syntax.InstEmptyWidth:
+			op := syntax.EmptyOp(inst.Arg)
+			if op&syntax.EmptyBeginText != 0 && !atStart {
+				continue
+			}
+			if op&syntax.EmptyEndText != 0 && !atEnd {
+				ret.pcs = append(ret.pcs, pc)
+				continue
+			}
+			stack = append(stack, int(inst.Out))
+		case syntax.InstMatch:
+			ret.matched = true
+		case syntax.InstRune, syntax.InstRune1, syntax.InstRuneAny, syntax.InstRuneAnyNotNL:
+			ret.pcs = append(ret.pcs, pc)
+		}
+	}
+	if ret.matched {
+		return patternState{matched: true}
+	}
+	sort.Ints(ret.pcs)
+	return ret
+}
+
+// patternAlphabet returns one rune for every range of runes that every instruction of both automata treats alike.
+// Runes that are easy to read are preferred so that counterexamples are readable.
+func patternAlphabet(automata ...*patternAutomaton) ([]rune, error) {
+	boundaries := map[rune]bool{0: true}
+	addRange := func(lo, hi rune) {
+		boundaries[lo] = true
+		boundaries[hi+1] = true
+	}
+	for _, automaton := range automata {
+		for _, inst := range automaton.prog.Inst {
+			switch inst.Op {
+			case syntax.InstRune:
+				foldCase := syntax.Flags(inst.Arg)&syntax.FoldCase != 0
+				for i := 0; i+1 < len(inst.Rune); i += 2 {
+					addRange(inst.Rune[i], inst.Rune[i+1])
+				}
+				if len(inst.Rune) == 1 {
+					addRange(inst.Rune[0], inst.Rune[0])
+				}
+				if foldCase {
+					if len(inst.Rune) != 1 {
+						return nil, fmt.Errorf("case insensitive character classes are not supported")
+					}
+					for r := unicode.SimpleFold(inst.Rune[0]); r != inst.Rune[0]; r = unicode.SimpleFold(r) {
+						addRange(r, r)
+					}
+				}
+			case syntax.InstRune1:
+				addRange(inst.Rune[0], inst.Rune[0])
+			case syntax.InstRuneAnyNotNL:
+				addRange('\n', '\n')
+			}
+		}
+	}
+
+	starts := []rune{}
+	for r := range boundaries {
+		if r <= unicode.MaxRune {
+			starts = append(starts, r)
+		}
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	alphabet := []rune{}
+	for i, lo := range starts {
+		hi := rune(unicode.MaxRune)
+		if i+1 < len(starts) {
+			hi = starts[i+1] - 1
+		}
+		alphabet = append(alphabet, readableRune(lo, hi))
+	}
+	return alphabet, nil
+}
+
+func readableRune(lo, hi rune) rune {
+	for _, r := range "a0A-_. " {
+		if lo <= r && r <= hi {
+			return r
+		}
+	}
+	for r := lo; r <= hi && r < lo+128; r++ {
+		if unicode.IsPrint(r) && utf8.ValidRune(r) && !strings.ContainsRune("\"\\", r) {
+			return r
+		}
+	}
+	return lo
+}
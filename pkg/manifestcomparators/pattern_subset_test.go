@@ -0,0 +1,61 @@
+package manifestcomparators
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestPatternAcceptsSuperset(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldPattern string
+		newPattern string
+		expected   bool
+		expectErr  bool
+	}{
+		{name: "identical", oldPattern: "^[a-z]+$", newPattern: "^[a-z]+$", expected: true},
+		{name: "equivalent", oldPattern: "^[a-z]+$", newPattern: "^[a-z][a-z]*$", expected: true},
+		{name: "widened class", oldPattern: "^[a-z]+$", newPattern: "^[a-z0-9]+$", expected: true},
+		{name: "narrowed class", oldPattern: "^[a-z0-9]+$", newPattern: "^[a-z]+$", expected: false},
+		{name: "bounded repetition", oldPattern: "^[a-z]+$", newPattern: "^[a-z]{1,5}$", expected: false},
+		{name: "dropped anchor", oldPattern: "^abc$", newPattern: "abc", expected: true},
+		{name: "added anchor", oldPattern: "abc", newPattern: "^abc", expected: false},
+		{name: "removed pattern", oldPattern: "^abc$", newPattern: "", expected: true},
+		{name: "added pattern", oldPattern: "", newPattern: "^abc$", expected: false},
+		{name: "unanchored substring", oldPattern: "abc", newPattern: "b", expected: true},
+		{name: "alternation", oldPattern: "^(foo|bar)$", newPattern: "^(foo|bar|baz)$", expected: true},
+		{name: "removed alternative", oldPattern: "^(foo|bar|baz)$", newPattern: "^(foo|bar)$", expected: false},
+		{name: "case insensitive", oldPattern: "^abc$", newPattern: "(?i)^ABC$", expected: true},
+		{name: "any character but newline", oldPattern: "^a.c$", newPattern: "^a[^\n]c$", expected: true},
+		{name: "empty string", oldPattern: "^$", newPattern: "^a*$", expected: true},
+		{name: "word boundary", oldPattern: `\bfoo\b`, newPattern: "foo", expectErr: true},
+		{name: "invalid", oldPattern: "^[a-z", newPattern: "^[a-z]$", expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, counterexample, err := patternAcceptsSuperset(test.oldPattern, test.newPattern)
+			switch {
+			case test.expectErr && err == nil:
+				t.Fatalf("expected error, got none")
+			case !test.expectErr && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case test.expectErr:
+				return
+			}
+			if actual != test.expected {
+				t.Fatalf("expected %v, got %v (counterexample %q)", test.expected, actual, counterexample)
+			}
+			if actual {
+				return
+			}
+
+			// the counterexample must actually tell the patterns apart.
+			if !regexp.MustCompile(test.oldPattern).MatchString(counterexample) {
+				t.Errorf("counterexample %q does not match old pattern %v", counterexample, test.oldPattern)
+			}
+			if regexp.MustCompile(test.newPattern).MatchString(counterexample) {
+				t.Errorf("counterexample %q matches new pattern %v", counterexample, test.newPattern)
+			}
+		})
+	}
+}
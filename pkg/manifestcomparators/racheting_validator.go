@@ -19,21 +19,13 @@ func RatchetCompare(validator SingleCRDValidator, existingCRD, newCRD *apiextens
 		return ComparisonResults{}, err
 	}
 
-	ret := ComparisonResults{
-		Name:         newResults.Name,
-		WhyItMatters: newResults.WhyItMatters,
-		Errors:       stringDiff(newResults.Errors, oldResults.Errors),
-		Warnings:     stringDiff(newResults.Warnings, oldResults.Warnings),
-		Infos:        stringDiff(newResults.Infos, oldResults.Infos),
-	}
-
-	return ret, nil
+	return NewComparisonResults(newResults.Name, newResults.WhyItMatters, findingDiff(newResults.Findings, oldResults.Findings)), nil
 }
 
-func stringDiff(s1 []string, s2 []string) []string {
-	ret := []string{}
+func findingDiff(s1 []Finding, s2 []Finding) []Finding {
+	ret := []Finding{}
 	for _, curr := range s1 {
-		if stringListContains(s2, curr) {
+		if findingListContains(s2, curr) {
 			continue
 		}
 
@@ -43,7 +35,7 @@ func stringDiff(s1 []string, s2 []string) []string {
 	return ret
 }
 
-func stringListContains(haystack []string, needle string) bool {
+func findingListContains(haystack []Finding, needle Finding) bool {
 	for _, straw := range haystack {
 		if straw == needle {
 			return true
@@ -70,7 +70,7 @@ func (r *crdComparatorRegistry) Compare(existingCRD, newCRD *apiextensionsv1.Cus
 	for _, comparator := range comparators {
 		currResults, err := comparator.Compare(existingCRD, newCRD)
 		if err != nil {
-			errs = append(errs, err)
+			errs = append(errs, &ComparatorError{Comparator: comparator.Name(), Err: err})
 			continue
 		}
 		ret = append(ret, currResults)
@@ -78,3 +78,18 @@ func (r *crdComparatorRegistry) Compare(existingCRD, newCRD *apiextensionsv1.Cus
 
 	return ret, errs
 }
+
+// ComparatorError is an evaluation error returned by a single comparator.  The message is the message of the
+// underlying error, the comparator is available for consumers that report per comparator.
+type ComparatorError struct {
+	Comparator string
+	Err        error
+}
+
+func (e *ComparatorError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ComparatorError) Unwrap() error {
+	return e.Err
+}
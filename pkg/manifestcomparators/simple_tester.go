@@ -12,6 +12,7 @@ import (
 	"github.com/openshift/crd-schema-checker/pkg/resourceread"
 	"gopkg.in/yaml.v2"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func AllTestsInDir(directory string) ([]ComparatorTest, error) {
@@ -54,7 +55,7 @@ func AllTestsInDir(directory string) ([]ComparatorTest, error) {
 func AllTestsInDirForComparator(comparator CRDComparator, directory string) ([]*simpleComparatorTest, error) {
 	registry := NewRegistry()
 	registry.AddComparator(comparator)
-	return AllTestsInDirForRegistry(registry, directory)
+	return allTestsInDir(registry, directory, false)
 }
 
 func AllTestsInDirForComparators(comparators []CRDComparator, directory string) ([]*simpleComparatorTest, error) {
@@ -62,7 +63,7 @@ func AllTestsInDirForComparators(comparators []CRDComparator, directory string)
 	for _, c := range comparators {
 		registry.AddComparator(c)
 	}
-	return AllTestsInDirForRegistry(registry, directory)
+	return allTestsInDir(registry, directory, false)
 }
 
 func RunAllTestsInDirForComparators(t *testing.T, comparators []CRDComparator, directory string) {
@@ -98,7 +99,13 @@ func RunAllTestsInDirForRegistry(t *testing.T, registry CRDComparatorRegistry, d
 	}
 }
 
+// AllTestsInDirForRegistry runs every test with every comparator of the registry.  expected.yaml only lists the
+// comparators a test is about, so the results of other comparators are not checked.
 func AllTestsInDirForRegistry(registry CRDComparatorRegistry, directory string) ([]*simpleComparatorTest, error) {
+	return allTestsInDir(registry, directory, true)
+}
+
+func allTestsInDir(registry CRDComparatorRegistry, directory string, listedComparatorsOnly bool) ([]*simpleComparatorTest, error) {
 	tests, err := AllTestsInDir(directory)
 	if err != nil {
 		return nil, err
@@ -107,8 +114,9 @@ func AllTestsInDirForRegistry(registry CRDComparatorRegistry, directory string)
 
 	for i := range tests {
 		ret = append(ret, &simpleComparatorTest{
-			ComparatorTest: tests[i],
-			registry:       registry,
+			ComparatorTest:        tests[i],
+			registry:              registry,
+			listedComparatorsOnly: listedComparatorsOnly,
 		})
 	}
 
@@ -207,11 +215,35 @@ type ComparatorTest struct {
 type simpleComparatorTest struct {
 	ComparatorTest ComparatorTest
 	registry       CRDComparatorRegistry
+	// listedComparatorsOnly skips the results of comparators that expected.yaml does not list.
+	listedComparatorsOnly bool
 }
 
 func (tc *simpleComparatorTest) Test(t *testing.T) {
 	actualResults, actualErrors := tc.registry.Compare(tc.ComparatorTest.ExistingCRD, tc.ComparatorTest.NewCRD)
+	if tc.listedComparatorsOnly {
+		actualResults = tc.ComparatorTest.ResultsOfListedComparators(actualResults)
+	}
+
 	tc.ComparatorTest.Test(t, actualResults, actualErrors)
+	tc.ComparatorTest.TestFindings(t, actualResults)
+}
+
+// ResultsOfListedComparators returns the results of the comparators that expected.yaml lists, for tests that run more
+// comparators than the test is about.
+func (tc *ComparatorTest) ResultsOfListedComparators(actualResults []ComparisonResults) []ComparisonResults {
+	listedComparators := sets.NewString()
+	for _, expected := range tc.ExpectedResults {
+		listedComparators.Insert(expected.Name)
+	}
+
+	ret := []ComparisonResults{}
+	for _, actual := range actualResults {
+		if listedComparators.Has(actual.Name) {
+			ret = append(ret, actual)
+		}
+	}
+	return ret
 }
 
 func (tc *ComparatorTest) Test(t *testing.T, actualResults []ComparisonResults, actualErrors []error) {
@@ -309,6 +341,43 @@ func (tc *ComparatorTest) Test(t *testing.T, actualResults []ComparisonResults,
 
 }
 
+// TestFindings checks the structured findings for every expected result that lists findings.  Expected results
+// without findings are only checked by their rendered messages.
+func (tc *ComparatorTest) TestFindings(t *testing.T, actualResults []ComparisonResults) {
+	for _, expected := range tc.ExpectedResults {
+		if len(expected.Findings) == 0 {
+			continue
+		}
+
+		expectedFindings := sortedFindings(expected.Findings)
+		expectedBytes, err := yaml.Marshal(expectedFindings)
+		if err != nil {
+			t.Error(err)
+		}
+
+		actualFindings := []Finding{}
+		if actualPtr := findResultsForComparator(expected.Name, actualResults); actualPtr != nil {
+			actualFindings = sortedFindings(actualPtr.Findings)
+		}
+		actualBytes, err := yaml.Marshal(actualFindings)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !reflect.DeepEqual(expectedFindings, actualFindings) {
+			t.Errorf("mismatched findings for expectedResults[%v]: expected\n%v\n, got\n%v\n", expected.Name, string(expectedBytes), string(actualBytes))
+		}
+	}
+}
+
+func sortedFindings(findings []Finding) []Finding {
+	ret := append([]Finding{}, findings...)
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].String() < ret[j].String()
+	})
+	return ret
+}
+
 func findResultsForComparator(name string, results []ComparisonResults) *ComparisonResults {
 	for i := range results {
 		if results[i].Name == name {
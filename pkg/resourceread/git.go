@@ -0,0 +1,149 @@
+package resourceread
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ErrNotInRevision is returned when the path to read did not exist at the revision.
+var ErrNotInRevision = errors.New("path does not exist at the revision")
+
+// ReadCustomResourceDefinitionManifestsFromGit reads every CRD in path as of ref in the git repository of the current
+// directory.  path is absolute or relative to the current directory and may be a file or a directory.  Filenames of the
+// returned manifests are relative to the root of the repository.
+//
+// Only path is read, as it was at ref.  CRDs that were in a different path at ref are not found, pass the path they
+// had then, the root of the repository to read every manifest at ref, or look them up by name with
+// FindCustomResourceDefinitionManifestsInGit.  A path that did not exist at ref is an ErrNotInRevision.
+func ReadCustomResourceDefinitionManifestsFromGit(ref, path string) ([]CustomResourceDefinitionManifest, error) {
+	repoPath, err := repositoryPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// --full-tree resolves repoPath against the root of the repository and keeps the names valid for cat-file.
+	listing, err := git("ls-tree", "-r", "-z", "--full-tree", "--name-only", ref, "--", repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(listing) == 0 {
+		return nil, fmt.Errorf("%v as of %v: %w", path, ref, ErrNotInRevision)
+	}
+
+	objectName := ref + ":" + repoPath
+	if repoPath == "." {
+		// the root tree has an empty path.
+		objectName = ref + ":"
+	}
+	objectType, err := git("cat-file", "-t", objectName)
+	if err != nil {
+		return nil, err
+	}
+	// a file named explicitly is read regardless of its extension, like ReadCustomResourceDefinitionManifests.
+	isFile := strings.TrimSpace(string(objectType)) == "blob"
+
+	ret := []CustomResourceDefinitionManifest{}
+	for _, filename := range strings.Split(string(listing), "\x00") {
+		if len(filename) == 0 {
+			continue
+		}
+		if !isFile && !isManifestFile(filename) {
+			continue
+		}
+
+		content, err := git("cat-file", "blob", ref+":"+filename)
+		if err != nil {
+			return nil, err
+		}
+		manifests, err := ReadCustomResourceDefinitionManifestsFromBytes(filename, content)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", ref, err)
+		}
+		ret = append(ret, manifests...)
+	}
+	return ret, nil
+}
+
+// FindCustomResourceDefinitionManifestsInGit reads the CRDs named in names from every manifest in the git repository of
+// the current directory as of ref, wherever they were.  Files that cannot be decoded are skipped, they do not hold the
+// CRDs being looked for.  A name defined in more than one manifest is an error, because either could be the CRD.
+func FindCustomResourceDefinitionManifestsInGit(ref string, names sets.String) ([]CustomResourceDefinitionManifest, error) {
+	listing, err := git("ls-tree", "-r", "-z", "--full-tree", "--name-only", ref)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []CustomResourceDefinitionManifest{}
+	filenamesByName := map[string]string{}
+	for _, filename := range strings.Split(string(listing), "\x00") {
+		if len(filename) == 0 || !isManifestFile(filename) {
+			continue
+		}
+
+		content, err := git("cat-file", "blob", ref+":"+filename)
+		if err != nil {
+			return nil, err
+		}
+		manifests, err := ReadCustomResourceDefinitionManifestsFromBytes(filename, content)
+		if err != nil {
+			continue
+		}
+		for _, manifest := range manifests {
+			if !names.Has(manifest.CRD.Name) {
+				continue
+			}
+			if other, ok := filenamesByName[manifest.CRD.Name]; ok {
+				return nil, fmt.Errorf("%v: crd/%v is defined in both %v and %v", ref, manifest.CRD.Name, other, filename)
+			}
+			filenamesByName[manifest.CRD.Name] = filename
+			ret = append(ret, manifest)
+		}
+	}
+	return ret, nil
+}
+
+// repositoryPath returns path relative to the root of the repository of the current directory, with / separators.
+func repositoryPath(path string) (string, error) {
+	topLevel, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	prefix, err := git("rev-parse", "--show-prefix")
+	if err != nil {
+		return "", err
+	}
+
+	relativePath := filepath.Join(strings.TrimSpace(string(prefix)), path)
+	if filepath.IsAbs(path) {
+		// the top level has its symlinks resolved, path can only be resolved when it exists in the working tree.
+		if resolvedPath, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolvedPath
+		}
+		relativePath, err = filepath.Rel(strings.TrimSpace(string(topLevel)), path)
+		if err != nil {
+			return "", err
+		}
+	}
+	if relativePath == ".." || strings.HasPrefix(relativePath, "../") {
+		return "", fmt.Errorf("%v is outside of the git repository %v", path, strings.TrimSpace(string(topLevel)))
+	}
+	return filepath.ToSlash(relativePath), nil
+}
+
+func git(args ...string) ([]byte, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %v failed: %w: %v", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
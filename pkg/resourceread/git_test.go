@@ -0,0 +1,207 @@
+package resourceread
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestReadCustomResourceDefinitionManifestsFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Chdir(t.TempDir())
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	mustGit := func(args ...string) {
+		t.Helper()
+		if _, err := git(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustWrite := func(filename, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mustGit("init", "-q")
+	mustWrite("manifests/crds.yaml", multiDocumentManifest)
+	mustWrite("manifests/README.md", "not a manifest")
+	mustGit("add", ".")
+	mustGit("-c", "commit.gpgsign=false", "commit", "-q", "-m", "initial")
+
+	// moving the file after the commit must not change what is read at the commit.
+	mustGit("mv", "manifests", "moved")
+	mustGit("-c", "commit.gpgsign=false", "commit", "-q", "-m", "move")
+
+	manifests, err := ReadCustomResourceDefinitionManifestsFromGit("HEAD~1", "manifests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("expected 2 CRDs, got %d", len(manifests))
+	}
+	for _, manifest := range manifests {
+		if manifest.Filename != "manifests/crds.yaml" {
+			t.Errorf("expected manifests/crds.yaml, got %v", manifest.Filename)
+		}
+	}
+	if expected, actual := (SourcePosition{Line: 24, Column: 13}), manifests[0].Positions.FieldPosition("v1", "^.spec"); actual != expected {
+		t.Errorf("expected %#v, got %#v", expected, actual)
+	}
+
+	// only the path is read at the revision, so CRDs that moved are found with their old path or the repository root.
+	if _, err := ReadCustomResourceDefinitionManifestsFromGit("HEAD", "manifests"); !errors.Is(err, ErrNotInRevision) {
+		t.Errorf("expected ErrNotInRevision for the moved directory, got %v", err)
+	}
+	manifests, err = ReadCustomResourceDefinitionManifestsFromGit("HEAD~1", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 2 {
+		t.Errorf("expected 2 CRDs in the repository, got %d", len(manifests))
+	}
+}
+
+func TestReadCustomResourceDefinitionManifestsFromGitFilenames(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repositoryDir := t.TempDir()
+	t.Chdir(repositoryDir)
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	mustGit := func(args ...string) {
+		t.Helper()
+		if _, err := git(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustWrite := func(filename, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mustGit("init", "-q")
+	// files without a manifest extension are only read when named explicitly, even if the directory has the same name.
+	mustWrite("crds/crds", multiDocumentManifest)
+	mustWrite("crds/nested/crds", multiDocumentManifest)
+	mustGit("add", ".")
+	mustGit("-c", "commit.gpgsign=false", "commit", "-q", "-m", "initial")
+
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{path: "crds", expected: 0},
+		{path: "crds/", expected: 0},
+		{path: "crds/crds", expected: 2},
+		{path: "crds/nested/crds", expected: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			manifests, err := ReadCustomResourceDefinitionManifestsFromGit("HEAD", test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(manifests) != test.expected {
+				t.Errorf("expected %d CRDs, got %d", test.expected, len(manifests))
+			}
+		})
+	}
+
+	if _, err := ReadCustomResourceDefinitionManifestsFromGit("HEAD", "missing"); !errors.Is(err, ErrNotInRevision) {
+		t.Errorf("expected ErrNotInRevision for a missing path, got %v", err)
+	}
+
+	t.Chdir("crds")
+	manifests, err := ReadCustomResourceDefinitionManifestsFromGit("HEAD", "crds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 2 || manifests[0].Filename != "crds/crds" {
+		t.Errorf("expected 2 CRDs from crds/crds relative to the current directory, got %d", len(manifests))
+	}
+
+	// absolute paths are resolved against the root of the repository, not the current directory.
+	manifests, err = ReadCustomResourceDefinitionManifestsFromGit("HEAD", filepath.Join(repositoryDir, "crds", "nested", "crds"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 2 || manifests[0].Filename != "crds/nested/crds" {
+		t.Errorf("expected 2 CRDs from the absolute path of crds/nested/crds, got %d", len(manifests))
+	}
+	if _, err := ReadCustomResourceDefinitionManifestsFromGit("HEAD", filepath.Dir(repositoryDir)); err == nil {
+		t.Errorf("expected an error for a path outside of the repository")
+	}
+}
+
+func TestFindCustomResourceDefinitionManifestsInGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Chdir(t.TempDir())
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	mustGit := func(args ...string) {
+		t.Helper()
+		if _, err := git(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustWrite := func(filename, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mustGit("init", "-q")
+	mustWrite("manifests/crds.yaml", multiDocumentManifest)
+	// files that cannot be decoded, like templates, do not hold the CRDs being looked for.
+	mustWrite("templates/crd.yaml", "{{ .Values.crd }}: [")
+	mustGit("add", ".")
+	mustGit("-c", "commit.gpgsign=false", "commit", "-q", "-m", "initial")
+
+	manifests, err := FindCustomResourceDefinitionManifestsInGit("HEAD", sets.NewString("widgets.example.com", "missing.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 1 || manifests[0].CRD.Name != "widgets.example.com" || manifests[0].Filename != "manifests/crds.yaml" {
+		t.Errorf("expected widgets.example.com from manifests/crds.yaml, got %v", manifests)
+	}
+
+	// a CRD defined twice cannot be told apart.
+	mustWrite("copy/crds.yaml", multiDocumentManifest)
+	mustGit("add", ".")
+	mustGit("-c", "commit.gpgsign=false", "commit", "-q", "-m", "copy")
+	if _, err := FindCustomResourceDefinitionManifestsInGit("HEAD", sets.NewString("widgets.example.com")); err == nil {
+		t.Errorf("expected an error for a CRD defined in two manifests")
+	}
+}
@@ -0,0 +1,137 @@
+package resourceread
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// CustomResourceDefinitionManifest is a CRD together with the manifest it was read from.
+type CustomResourceDefinitionManifest struct {
+	CRD       *apiextensionsv1.CustomResourceDefinition
+	Filename  string
+	Positions *CustomResourceDefinitionPositions
+}
+
+// ReadCustomResourceDefinitionsV1WithPositions decodes every CRD in a multi-document yaml or json manifest.
+// Documents that are not CRDs are skipped.
+func ReadCustomResourceDefinitionsV1WithPositions(objBytes []byte) ([]*apiextensionsv1.CustomResourceDefinition, []*CustomResourceDefinitionPositions, error) {
+	crds := []*apiextensionsv1.CustomResourceDefinition{}
+	positions := []*CustomResourceDefinitionPositions{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(objBytes))
+	for i := 0; ; i++ {
+		root := &yaml.Node{}
+		err := decoder.Decode(root)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		document := root
+		if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+			document = root.Content[0]
+		}
+		if !isCustomResourceDefinition(document) {
+			continue
+		}
+
+		// the node tree is decoded from the whole manifest, so re-encoding one document keeps line numbers of the node
+		// tree relative to objBytes.
+		documentBytes, err := yaml.Marshal(document)
+		if err != nil {
+			return nil, nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		crd, err := ReadCustomResourceDefinitionV1(documentBytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		crds = append(crds, crd)
+		positions = append(positions, &CustomResourceDefinitionPositions{document: document})
+	}
+
+	return crds, positions, nil
+}
+
+func isCustomResourceDefinition(document *yaml.Node) bool {
+	_, kind := mappingValue(document, "kind")
+	_, apiVersion := mappingValue(document, "apiVersion")
+	return kind != nil && kind.Value == "CustomResourceDefinition" &&
+		apiVersion != nil && strings.HasPrefix(apiVersion.Value, "apiextensions.k8s.io/")
+}
+
+// ReadCustomResourceDefinitionManifests reads every CRD in path.  When path is a directory, every .yaml, .yml, and
+// .json file below it is read in lexical order.
+func ReadCustomResourceDefinitionManifests(path string) ([]CustomResourceDefinitionManifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	filenames := []string{path}
+	if info.IsDir() {
+		filenames = []string{}
+		err := filepath.WalkDir(path, func(filename string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && isManifestFile(filename) {
+				filenames = append(filenames, filename)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(filenames)
+	}
+
+	ret := []CustomResourceDefinitionManifest{}
+	for _, filename := range filenames {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		manifests, err := ReadCustomResourceDefinitionManifestsFromBytes(filename, content)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, manifests...)
+	}
+	return ret, nil
+}
+
+func isManifestFile(filename string) bool {
+	switch filepath.Ext(filename) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// ReadCustomResourceDefinitionManifestsFromBytes reads every CRD in content, which was read from filename.
+func ReadCustomResourceDefinitionManifestsFromBytes(filename string, content []byte) ([]CustomResourceDefinitionManifest, error) {
+	crds, positions, err := ReadCustomResourceDefinitionsV1WithPositions(content)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %v: %w", filename, err)
+	}
+	ret := []CustomResourceDefinitionManifest{}
+	for i := range crds {
+		ret = append(ret, CustomResourceDefinitionManifest{
+			CRD:       crds[i],
+			Filename:  filename,
+			Positions: positions[i],
+		})
+	}
+	return ret, nil
+}
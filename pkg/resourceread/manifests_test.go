@@ -0,0 +1,52 @@
+package resourceread
+
+import (
+	"testing"
+)
+
+const multiDocumentManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: skipped
+---
+` + positionsCRD + `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: gadgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Gadget
+    plural: gadgets
+  scope: Namespaced
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+---
+`
+
+func TestReadCustomResourceDefinitionsV1WithPositions(t *testing.T) {
+	crds, positions, err := ReadCustomResourceDefinitionsV1WithPositions([]byte(multiDocumentManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(crds) != 2 || len(positions) != 2 {
+		t.Fatalf("expected 2 CRDs, got %d", len(crds))
+	}
+	if crds[0].Name != "widgets.example.com" || crds[1].Name != "gadgets.example.com" {
+		t.Errorf("unexpected CRDs %v and %v", crds[0].Name, crds[1].Name)
+	}
+
+	// positions are relative to the whole manifest, not the document.
+	if expected, actual := (SourcePosition{Line: 24, Column: 13}), positions[0].FieldPosition("v1", "^.spec"); actual != expected {
+		t.Errorf("expected %#v, got %#v", expected, actual)
+	}
+	if expected, actual := (SourcePosition{Line: 54, Column: 9}), positions[1].FieldPosition("v1", "^"); actual != expected {
+		t.Errorf("expected %#v, got %#v", expected, actual)
+	}
+}
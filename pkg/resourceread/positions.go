@@ -0,0 +1,141 @@
+package resourceread
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourcePosition is a one-based line and column in a manifest.
+type SourcePosition struct {
+	Line   int
+	Column int
+}
+
+// CustomResourceDefinitionPositions maps schema fields of a CRD back to where they are declared in the manifest.
+type CustomResourceDefinitionPositions struct {
+	document *yaml.Node
+}
+
+// FieldPosition returns the position of the field identified by the simple location (^.spec.foo[*].bar) in the
+// schema of version.  When the field cannot be found, for instance because it was removed, the position of the
+// closest declared ancestor is returned.  An empty version returns the position of the CRD itself.
+func (p *CustomResourceDefinitionPositions) FieldPosition(version, simpleLocation string) SourcePosition {
+	if p == nil || p.document == nil {
+		return SourcePosition{Line: 1, Column: 1}
+	}
+	closest := p.document
+
+	versionNode := findVersionNode(p.document, version)
+	if versionNode == nil {
+		return positionOf(closest)
+	}
+	closest = versionNode
+
+	_, schemaNode := mappingValue(versionNode, "schema")
+	schemaKey, schemaNode := mappingValue(schemaNode, "openAPIV3Schema")
+	if schemaNode == nil {
+		return positionOf(closest)
+	}
+	closest = schemaKey
+
+	current := schemaNode
+	for _, step := range simpleLocationSteps(simpleLocation) {
+		var key, next *yaml.Node
+		switch {
+		case step == "[*]":
+			if key, next = mappingValue(current, "items"); next == nil {
+				key, next = mappingValue(current, "additionalProperties")
+			}
+		case strings.HasPrefix(step, "["):
+			_, items := mappingValue(current, "items")
+			if item := sequenceItem(items, strings.Trim(step, "[]")); item != nil {
+				key, next = item, item
+			}
+		default:
+			_, properties := mappingValue(current, "properties")
+			key, next = mappingValue(properties, step)
+		}
+		if next == nil {
+			break
+		}
+		closest = key
+		current = next
+	}
+
+	return positionOf(closest)
+}
+
+func positionOf(node *yaml.Node) SourcePosition {
+	return SourcePosition{Line: node.Line, Column: node.Column}
+}
+
+// simpleLocationSteps splits ^.spec.foo[*].bar into spec, foo, [*], bar.
+func simpleLocationSteps(simpleLocation string) []string {
+	steps := []string{}
+	remaining := strings.TrimPrefix(simpleLocation, "^")
+	for len(remaining) > 0 {
+		switch remaining[0] {
+		case '.':
+			remaining = remaining[1:]
+			end := strings.IndexAny(remaining, ".[")
+			if end < 0 {
+				end = len(remaining)
+			}
+			steps = append(steps, remaining[:end])
+			remaining = remaining[end:]
+		case '[':
+			end := strings.Index(remaining, "]")
+			if end < 0 {
+				return steps
+			}
+			steps = append(steps, remaining[:end+1])
+			remaining = remaining[end+1:]
+		default:
+			return steps
+		}
+	}
+	return steps
+}
+
+func findVersionNode(document *yaml.Node, version string) *yaml.Node {
+	if len(version) == 0 {
+		return nil
+	}
+	_, spec := mappingValue(document, "spec")
+	_, versions := mappingValue(spec, "versions")
+	if versions == nil || versions.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, versionNode := range versions.Content {
+		if _, name := mappingValue(versionNode, "name"); name != nil && name.Value == version {
+			return versionNode
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the key and value nodes of name in a mapping node, or nils when it is not present.
+func mappingValue(node *yaml.Node, name string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func sequenceItem(node *yaml.Node, index string) *yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	i, err := strconv.Atoi(index)
+	if err != nil || i < 0 || i >= len(node.Content) {
+		return nil
+	}
+	return node.Content[i]
+}
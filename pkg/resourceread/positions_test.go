@@ -0,0 +1,68 @@
+package resourceread
+
+import "testing"
+
+const positionsCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                names:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      name:
+                        type: string
+                labels:
+                  type: object
+                  additionalProperties:
+                    type: string
+`
+
+func TestFieldPosition(t *testing.T) {
+	_, allPositions, err := ReadCustomResourceDefinitionsV1WithPositions([]byte(positionsCRD))
+	if err != nil {
+		t.Fatal(err)
+	}
+	positions := allPositions[0]
+
+	tests := []struct {
+		name           string
+		version        string
+		simpleLocation string
+		expected       SourcePosition
+	}{
+		{name: "no version", version: "", simpleLocation: "^.spec", expected: SourcePosition{Line: 1, Column: 1}},
+		{name: "unknown version", version: "v2", simpleLocation: "^.spec", expected: SourcePosition{Line: 1, Column: 1}},
+		{name: "root", version: "v1", simpleLocation: "^", expected: SourcePosition{Line: 16, Column: 9}},
+		{name: "property", version: "v1", simpleLocation: "^.spec", expected: SourcePosition{Line: 19, Column: 13}},
+		{name: "list item property", version: "v1", simpleLocation: "^.spec.names[*].name", expected: SourcePosition{Line: 27, Column: 23}},
+		{name: "map value", version: "v1", simpleLocation: "^.spec.labels[*]", expected: SourcePosition{Line: 31, Column: 19}},
+		{name: "removed field uses parent", version: "v1", simpleLocation: "^.spec.removed.child", expected: SourcePosition{Line: 19, Column: 13}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := positions.FieldPosition(test.version, test.simpleLocation)
+			if actual != test.expected {
+				t.Errorf("expected %#v, got %#v", test.expected, actual)
+			}
+		})
+	}
+}